@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+)
+
+func newBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "List and restore automatic config backups",
+		Long: fmt.Sprintf("Every save keeps the previous file as a timestamped backup (the newest %d are kept "+
+			"per scope file). User config backups sit next to config.json; project settings backups are kept "+
+			"under ~/.copilot/ccc/backups so they are never committed. Use --scope to select the file.", config.MaxBackups),
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:          "list",
+		Short:        "List backups of the selected scope's config file, newest first",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runBackupList,
+	}, &cobra.Command{
+		Use:          "restore <id>",
+		Short:        "Restore a backup over the selected scope's config file",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runBackupRestore,
+	})
+	return cmd
+}
+
+func runBackupList(cmd *cobra.Command, _ []string) error {
+	scope, projectDir, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	path := config.ScopePathFor(scope, projectDir)
+
+	backups, err := config.ListBackups(path)
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No backups of %s\n", path)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTIME\tSIZE")
+	for _, b := range backups {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", b.ID, b.Time.Local().Format(time.DateTime), b.Size)
+	}
+	return w.Flush()
+}
+
+func runBackupRestore(cmd *cobra.Command, args []string) error {
+	scope, projectDir, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	path := config.ScopePathFor(scope, projectDir)
+
+	if err := config.RestoreScopeBackup(scope, path, args[0]); err != nil {
+		return sharedHint(err)
+	}
+	slog.Info("backup restored", "id", args[0], "path", path)
+	fmt.Fprintf(cmd.OutOrStdout(), "Restored %s from backup %s\n", path, args[0])
+	return nil
+}
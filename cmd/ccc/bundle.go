@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsburckhardt/co-config/internal/bundle"
+	"github.com/jsburckhardt/co-config/internal/config"
+	"github.com/jsburckhardt/co-config/internal/copilot"
+	"github.com/jsburckhardt/co-config/internal/validate"
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [file]",
+		Short: "Export settings to a portable JSON or YAML bundle",
+		Long: "Export the selected scope's settings to a bundle that can be shared and imported on another machine. " +
+			"Sensitive keys (tokens, logged-in users) and token-like values are always left out. " +
+			"Without a file the bundle is written to stdout.",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runExport,
+	}
+	cmd.Flags().String("format", "", "Bundle format: json or yaml (default: from the file extension, else json)")
+	cmd.Flags().StringSlice("keys", nil, "Only export these keys")
+	cmd.Flags().StringSlice("category", nil, fmt.Sprintf("Only export keys in these categories (%s)", strings.Join(copilot.Categories, ", ")))
+	return cmd
+}
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import settings from a JSON or YAML bundle",
+		Long: "Import a bundle created by `ccc export` into the selected scope. With --mode merge (the default) the bundle's " +
+			"keys are added or overwritten and all other keys are kept; with --mode replace every non-sensitive key " +
+			"that is not in the bundle is removed. Sensitive keys are never imported or removed.",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runImport,
+	}
+	cmd.Flags().String("mode", string(bundle.ModeMerge), "How to combine the bundle with the existing file: merge or replace")
+	cmd.Flags().Bool("dry-run", false, "Show the changes without writing the file")
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	_, path, cfg, err := loadScopeConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatStr, _ := cmd.Flags().GetString("format")
+	format := bundle.FormatJSON
+	if formatStr != "" {
+		if format, err = bundle.ParseFormat(formatStr); err != nil {
+			return err
+		}
+	} else if len(args) == 1 {
+		format = bundle.FormatForPath(args[0])
+	}
+
+	keys, _ := cmd.Flags().GetStringSlice("keys")
+	categories, _ := cmd.Flags().GetStringSlice("category")
+	if err := checkCategories(categories); err != nil {
+		return err
+	}
+
+	b, stripped := bundle.Export(cfg, bundle.Filter{Keys: keys, Categories: categories})
+	for _, key := range stripped {
+		fmt.Fprintf(cmd.ErrOrStderr(), "skipped sensitive key %q\n", key)
+	}
+	data, err := b.Marshal(format)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(args[0], data, 0600); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	slog.Info("settings exported", "path", path, "bundle", args[0], "keys", len(b.Settings))
+	fmt.Fprintf(cmd.OutOrStdout(), "Exported %d key(s) from %s to %s\n", len(b.Settings), path, args[0])
+	return nil
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	modeStr, _ := cmd.Flags().GetString("mode")
+	mode, err := bundle.ParseMode(modeStr)
+	if err != nil {
+		return err
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading bundle: %w", err)
+	}
+	b, stripped, err := bundle.Parse(data)
+	if err != nil {
+		return err
+	}
+	for _, key := range stripped {
+		fmt.Fprintf(cmd.ErrOrStderr(), "skipped sensitive key %q\n", key)
+	}
+	printSkippedFolders(cmd, b.SkipMissingFolders())
+	if err := validate.Settings(detectSchema(cmd), b.Settings); err != nil {
+		return err
+	}
+
+	scope, path, cfg, err := loadScopeConfig(cmd)
+	if err != nil {
+		return err
+	}
+	changes := b.Apply(cfg, mode)
+	printChanges(cmd, changes)
+	if len(changes) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s is already up to date\n", path)
+		return nil
+	}
+	if dryRun {
+		return nil
+	}
+
+	if err := saveScopeConfig(scope, path, cfg); err != nil {
+		return err
+	}
+	slog.Info("settings imported", "path", path, "bundle", args[0], "mode", mode, "changes", len(changes))
+	fmt.Fprintf(cmd.OutOrStdout(), "Imported %d change(s) into %s\n", len(changes), path)
+	return nil
+}
+
+// checkCategories rejects category names FieldCategory never returns.
+func checkCategories(categories []string) error {
+	for _, c := range categories {
+		found := false
+		for _, known := range copilot.Categories {
+			if strings.EqualFold(c, known) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown category %q: must be one of %s", c, strings.Join(copilot.Categories, ", "))
+		}
+	}
+	return nil
+}
+
+// printSkippedFolders warns about trusted folders left out of an import
+// because they do not exist on this machine.
+func printSkippedFolders(cmd *cobra.Command, folders []string) {
+	for _, dir := range folders {
+		fmt.Fprintf(cmd.ErrOrStderr(), "skipped trusted folder %q: it does not exist on this machine\n", dir)
+	}
+}
+
+// printChanges lists changes as "key: old → new", with +/- lines for list items.
+func printChanges(cmd *cobra.Command, changes []config.Change) {
+	out := cmd.OutOrStdout()
+	for _, c := range changes {
+		switch {
+		case c.IsList():
+			fmt.Fprintf(out, "%s:\n", c.Key)
+			for _, item := range c.Removed {
+				fmt.Fprintf(out, "  - %s\n", changeValue(c.Key, item, true))
+			}
+			for _, item := range c.Added {
+				fmt.Fprintf(out, "  + %s\n", changeValue(c.Key, item, true))
+			}
+		default:
+			fmt.Fprintf(out, "%s: %s → %s\n", c.Key, changeValue(c.Key, c.Old, c.OldSet), changeValue(c.Key, c.New, c.NewSet))
+		}
+	}
+}
+
+// changeValue renders one side of a change for printChanges, masking sensitive values.
+func changeValue(key string, value any, set bool) string {
+	if !set {
+		return "(unset)"
+	}
+	return maskedValue(key, value)
+}
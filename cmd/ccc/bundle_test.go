@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UT-CLI-005: export, import and profile save never print or write a nested token
+func TestBundleCommandsKeepNestedTokens(t *testing.T) {
+	home := newCCCHome(t, nestedTokenConfig)
+
+	out, err := runCCC(t, home, "export")
+	if err != nil || !strings.Contains(out, `"theme"`) || strings.Contains(out, "ghp_nestedsecret") {
+		t.Errorf("export = %v\n%s", err, out)
+	}
+
+	bundlePath := filepath.Join(home, "bundle.json")
+	if err := os.WriteFile(bundlePath, []byte(`{"version": 1, "settings": {"theme": "light"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runCCC(t, home, "import", bundlePath, "--mode", "replace")
+	if err != nil || !strings.Contains(out, "theme: dark → light") || strings.Contains(out, "ghp_nestedsecret") {
+		t.Errorf("import = %v\n%s", err, out)
+	}
+	if cfg := readUserConfig(t, home); !strings.Contains(cfg, "ghp_nestedsecret") {
+		t.Errorf("replace must keep the entry holding the token:\n%s", cfg)
+	}
+
+	if out, err := runCCC(t, home, "profile", "save", "work"); err != nil {
+		t.Fatalf("profile save: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(filepath.Join(home, "copilot", "ccc", "profiles", "work.json"))
+	if err != nil || strings.Contains(string(data), "ghp_nestedsecret") {
+		t.Errorf("profile = %v\n%s", err, data)
+	}
+}
+
+// UT-CLI-006: import, profile diff and profile apply skip trusted folders missing on this machine instead of failing
+func TestImportSkipsMissingTrustedFolders(t *testing.T) {
+	home := newCCCHome(t, `{"theme": "dark"}`)
+	missing := filepath.Join(home, "other-machine", "repo")
+	settings := fmt.Sprintf(`{"version": 1, "settings": {"theme": "light", "trusted_folders": [%q, %q]}}`, home, missing)
+
+	bundlePath := filepath.Join(home, "bundle.json")
+	if err := os.WriteFile(bundlePath, []byte(settings), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runCCC(t, home, "import", bundlePath)
+	if err != nil || !strings.Contains(out, fmt.Sprintf("skipped trusted folder %q", missing)) {
+		t.Fatalf("import = %v\n%s", err, out)
+	}
+	if cfg := readUserConfig(t, home); !strings.Contains(cfg, `"light"`) || !strings.Contains(cfg, home) || strings.Contains(cfg, missing) {
+		t.Errorf("import should keep the existing folder only:\n%s", cfg)
+	}
+
+	profileDir := filepath.Join(home, "copilot", "ccc", "profiles")
+	if err := os.MkdirAll(profileDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(profileDir, "laptop.json"), []byte(settings), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runCCC(t, home, "profile", "diff", "laptop")
+	if err != nil || !strings.Contains(out, fmt.Sprintf("skipped trusted folder %q", missing)) || strings.Contains(out, "+ "+missing) {
+		t.Errorf("profile diff should preview the skipped folder like apply = %v\n%s", err, out)
+	}
+	out, err = runCCC(t, home, "profile", "apply", "laptop")
+	if err != nil || !strings.Contains(out, fmt.Sprintf("skipped trusted folder %q", missing)) {
+		t.Errorf("profile apply = %v\n%s", err, out)
+	}
+}
+
+// UT-CLI-007: profile diff rejects a profile with invalid values like profile apply does
+func TestProfileDiffValidates(t *testing.T) {
+	home := newCCCHome(t, `{"theme": "dark"}`)
+	profileDir := filepath.Join(home, "copilot", "ccc", "profiles")
+	if err := os.MkdirAll(profileDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	bad := `{"version": 1, "settings": {"allowed_urls": ["github.com", "github.com"]}}`
+	if err := os.WriteFile(filepath.Join(profileDir, "bad.json"), []byte(bad), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, sub := range []string{"diff", "apply"} {
+		if out, err := runCCC(t, home, "profile", sub, "bad"); err == nil || !strings.Contains(err.Error(), "allowed_urls") {
+			t.Errorf("profile %s bad = %v\n%s", sub, err, out)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsburckhardt/co-config/internal/schemacache"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cached Copilot CLI schema",
+		Long: "ccc caches the parsed `copilot help config` and `copilot help environment` output per Copilot CLI " +
+			"version in " + schemacache.DefaultDir() + ", so startup only runs `copilot version` until Copilot " +
+			"is upgraded. Use --refresh-schema on any command to detect the schema again.",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:          "clear",
+		Short:        "Remove every cached schema",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runCacheClear,
+	})
+	return cmd
+}
+
+func runCacheClear(cmd *cobra.Command, _ []string) error {
+	dir := schemacache.DefaultDir()
+	removed, err := schemacache.NewStore(dir).Clear()
+	if err != nil {
+		return err
+	}
+	slog.Info("schema cache cleared", "dir", dir, "entries", removed)
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed %d cached schema(s) from %s\n", removed, dir)
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+	"github.com/jsburckhardt/co-config/internal/doctor"
+)
+
+func newDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the Copilot CLI installation and config files for common problems",
+		Long: "Run health checks: the Copilot CLI binary and version, schema parsing, JSON validity and " +
+			"permissions of every scope's config file, plain-text tokens, missing trusted folders, conflicting " +
+			"allowed/denied URLs, and environment variables that override config values. Exits non-zero " +
+			"when a check fails.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runDoctor,
+	}
+	addOutputFlag(cmd)
+	return cmd
+}
+
+func runDoctor(cmd *cobra.Command, _ []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	_, projectDir, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+
+	checks := doctor.Run(doctor.Input{
+		Detection: detect(cmd),
+		Paths:     config.ScopePaths(projectDir),
+		LookupEnv: os.LookupEnv,
+	})
+
+	if format != outputTable {
+		err = writeStructured(cmd.OutOrStdout(), format, checks)
+	} else {
+		err = printChecks(cmd, checks)
+	}
+	if err != nil {
+		return err
+	}
+	if n := doctor.Failed(checks); n > 0 {
+		return fmt.Errorf("%d check(s) failed", n)
+	}
+	return nil
+}
+
+// statusMarks are the table markers for each check status.
+var statusMarks = map[doctor.Status]string{
+	doctor.StatusPass: "✓ pass",
+	doctor.StatusWarn: "! warn",
+	doctor.StatusFail: "✗ fail",
+}
+
+// printChecks prints one line per check with its details indented below.
+func printChecks(cmd *cobra.Command, checks []doctor.Check) error {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	counts := map[doctor.Status]int{}
+	for _, c := range checks {
+		counts[c.Status]++
+		fmt.Fprintf(w, "%s\t%s\t%s\n", statusMarks[c.Status], c.Name, c.Message)
+		for _, d := range c.Details {
+			fmt.Fprintf(w, "\t\t  %s\n", d)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "\n%d passed, %d warnings, %d failed\n",
+		counts[doctor.StatusPass], counts[doctor.StatusWarn], counts[doctor.StatusFail])
+	return nil
+}
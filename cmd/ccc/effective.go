@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+	"github.com/jsburckhardt/co-config/internal/sensitive"
+)
+
+func newEffectiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "effective",
+		Short:        "Show the merged configuration Copilot will use and where each value comes from",
+		Long:         "Merge the user, project and project-local config files with Copilot's precedence (later scopes win) and print each key's winning value and the scope file it came from.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runEffective,
+	}
+}
+
+func runEffective(cmd *cobra.Command, _ []string) error {
+	_, projectDir, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+
+	paths := config.ScopePaths(projectDir)
+	layers, err := config.LoadLayers(paths)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSCOPE\tFILE")
+	for _, ev := range config.Merge(layers) {
+		value := maskedValue(ev.Key, ev.Value)
+		source := ev.Scope.String()
+		if len(ev.Overrides) > 0 {
+			var overridden []string
+			for _, s := range ev.Overrides {
+				overridden = append(overridden, s.String())
+			}
+			source += " (overrides " + strings.Join(overridden, ", ") + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", ev.Key, value, source, paths[ev.Scope])
+	}
+	return w.Flush()
+}
+
+// maskedValue formats a value for display, masking sensitive and token-like values.
+func maskedValue(key string, value any) string {
+	if config.IsSensitiveEntry(key, value) {
+		return sensitive.MaskValue(value)
+	}
+	out, err := formatValue(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return out
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+	"github.com/jsburckhardt/co-config/internal/copilot"
+	"github.com/jsburckhardt/co-config/internal/sensitive"
+	"github.com/jsburckhardt/co-config/internal/validate"
+)
+
+func newGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "get <key>",
+		Short:        "Print the value of a config key",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runGet,
+	}
+}
+
+func newSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a config key",
+		Long: "Set a config key in the selected scope. The value is converted using the type reported by " +
+			"`copilot help config`: booleans accept true/false/on/off, enums must be one of the listed options, " +
+			"and lists accept a JSON array or a comma-separated list.",
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE:         runSet,
+	}
+}
+
+func newUnsetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "unset <key>",
+		Short:        "Remove a config key so Copilot falls back to its default",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runUnset,
+	}
+}
+
+func runGet(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	if err := refuseSensitive(key); err != nil {
+		return err
+	}
+
+	_, path, cfg, err := loadScopeConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	value := cfg.Get(key)
+	if value == nil {
+		return fmt.Errorf("key %q is not set in %s", key, path)
+	}
+	if config.IsSensitiveEntry(key, value) {
+		return fmt.Errorf("key %q holds a token-like value and cannot be printed", key)
+	}
+
+	out, err := formatValue(value)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), out)
+	return nil
+}
+
+func runSet(cmd *cobra.Command, args []string) error {
+	key, raw := args[0], args[1]
+	if err := refuseSensitive(key); err != nil {
+		return err
+	}
+
+	scope, path, cfg, err := loadScopeConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if config.IsSensitiveEntry(key, cfg.Get(key)) {
+		return fmt.Errorf("key %q holds a token-like value and cannot be modified", key)
+	}
+
+	schema := detectSchema(cmd)
+	if _, ok := copilot.FindField(schema, key); !ok {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %q is not in the detected Copilot config schema; inferring its type from the value\n", key)
+	}
+	value, err := coerceValue(schema, key, raw)
+	if err != nil {
+		return err
+	}
+	if config.IsSensitiveEntry(key, value) {
+		return fmt.Errorf("key %q holds a token-like value and cannot be modified", key)
+	}
+
+	cfg.Set(key, value)
+	if err := saveScopeConfig(scope, path, cfg); err != nil {
+		return err
+	}
+	slog.Info("config key set", "key", key, "path", path)
+	return nil
+}
+
+func runUnset(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	if err := refuseSensitive(key); err != nil {
+		return err
+	}
+
+	scope, path, cfg, err := loadScopeConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if cfg.Get(key) == nil {
+		return nil
+	}
+
+	// A value holding a token may be removed: that takes the secret out of the file.
+	cfg.Delete(key)
+	if err := saveScopeConfig(scope, path, cfg); err != nil {
+		return err
+	}
+	slog.Info("config key unset", "key", key, "path", path)
+	return nil
+}
+
+// loadScopeConfig resolves --scope and loads that scope's config file.
+func loadScopeConfig(cmd *cobra.Command) (config.Scope, string, *config.Config, error) {
+	scope, projectDir, err := resolveScope(cmd)
+	if err != nil {
+		return scope, "", nil, err
+	}
+	path := config.ScopePathFor(scope, projectDir)
+	cfg, err := loadConfigOrEmpty(path)
+	if err != nil {
+		return scope, path, nil, err
+	}
+	return scope, path, cfg, nil
+}
+
+// saveScopeConfig saves cfg to the file of scope at path. Sensitive values
+// are never written to the shared project settings; the error suggests
+// keeping them in settings.local.json instead.
+func saveScopeConfig(scope config.Scope, path string, cfg *config.Config) error {
+	return sharedHint(config.SaveScopeConfig(scope, path, cfg))
+}
+
+// sharedHint adds to an ErrSensitiveShared error where the values belong instead.
+func sharedHint(err error) error {
+	if errors.Is(err, config.ErrSensitiveShared) {
+		return fmt.Errorf("%w; settings.json is shared with the project, keep them in settings.local.json "+
+			"(--scope local, or `ccc move <key> --from project --to local`)", err)
+	}
+	return err
+}
+
+// refuseSensitive rejects keys that ccc never reads or writes from the command line.
+func refuseSensitive(key string) error {
+	if sensitive.IsSensitive(key) {
+		return fmt.Errorf("key %q is sensitive and cannot be read or modified with ccc", key)
+	}
+	return nil
+}
+
+// coerceValue converts raw using the schema type of key and validates the
+// result. Keys missing from the schema are parsed as a JSON literal when
+// possible and stored as a string otherwise.
+func coerceValue(schema []copilot.SchemaField, key, raw string) (any, error) {
+	if field, ok := copilot.FindField(schema, key); ok {
+		value, err := field.ParseValue(raw)
+		if err != nil {
+			return nil, err
+		}
+		if err := validate.Value(field, value); err != nil {
+			return nil, fmt.Errorf("%w: %s", copilot.ErrInvalidValue, err)
+		}
+		return value, nil
+	}
+	var v any
+	if err := json.Unmarshal([]byte(raw), &v); err == nil && v != nil {
+		return v, nil
+	}
+	return raw, nil
+}
+
+// formatValue renders strings as-is and everything else as compact JSON.
+func formatValue(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("formatting value: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// nestedTokenConfig holds a token nested inside an object.
+const nestedTokenConfig = `{"theme": "dark", "mcp": {"env": {"TOKEN": "ghp_nestedsecret"}}}`
+
+// UT-CLI-002: get prints plain values and refuses sensitive keys and nested tokens
+func TestGet(t *testing.T) {
+	home := newCCCHome(t, nestedTokenConfig)
+
+	out, err := runCCC(t, home, "get", "theme")
+	if err != nil || strings.TrimSpace(out) != "dark" {
+		t.Errorf("get theme = %q, %v; want dark", out, err)
+	}
+	for _, key := range []string{"mcp", "copilot_tokens"} {
+		out, err := runCCC(t, home, "get", key)
+		if err == nil {
+			t.Errorf("get %s should be refused", key)
+		}
+		if strings.Contains(out, "ghp_nestedsecret") {
+			t.Errorf("get %s leaks the token: %s", key, out)
+		}
+	}
+	if _, err := runCCC(t, home, "get", "beep"); err == nil {
+		t.Error("get of an unset key should fail")
+	}
+}
+
+// UT-CLI-003: set converts and validates the value and never touches nested tokens
+func TestSet(t *testing.T) {
+	home := newCCCHome(t, nestedTokenConfig)
+
+	if out, err := runCCC(t, home, "set", "theme", "light"); err != nil {
+		t.Fatalf("set theme light: %v\n%s", err, out)
+	}
+	if out, _ := runCCC(t, home, "get", "theme"); strings.TrimSpace(out) != "light" {
+		t.Errorf("theme = %q after set, want light", out)
+	}
+
+	before := readUserConfig(t, home)
+	for _, args := range [][]string{
+		{"set", "theme", "neon"},                               // not an option
+		{"set", "trusted_folders", "relative/dir"},             // validate.Value rejects the item
+		{"set", "allowed_urls", `["github.com","github.com"]`}, // validate.Value rejects duplicates
+		{"set", "mcp", `{"env": {}}`},                          // holds a nested token
+		{"set", "copilot_tokens", "{}"},                        // sensitive key
+		{"set", "foo", `{"a": "ghp_abc"}`},                     // the new value holds a token
+		{"set", "theme", "ghp_abcdef"},                         // the new value is a token
+	} {
+		if out, err := runCCC(t, home, args...); err == nil {
+			t.Errorf("%v should be rejected\n%s", args, out)
+		}
+	}
+	if after := readUserConfig(t, home); after != before {
+		t.Errorf("rejected sets must not write the config:\n%s", after)
+	}
+}
+
+// UT-CLI-004: unset removes a key, including one holding a nested token, and refuses sensitive keys
+func TestUnset(t *testing.T) {
+	home := newCCCHome(t, nestedTokenConfig)
+
+	if out, err := runCCC(t, home, "unset", "theme"); err != nil {
+		t.Fatalf("unset theme: %v\n%s", err, out)
+	}
+	if _, err := runCCC(t, home, "get", "theme"); err == nil {
+		t.Error("theme should be unset")
+	}
+	if _, err := runCCC(t, home, "unset", "beep"); err != nil {
+		t.Errorf("unset of a missing key should succeed: %v", err)
+	}
+
+	before := readUserConfig(t, home)
+	if _, err := runCCC(t, home, "unset", "copilot_tokens"); err == nil {
+		t.Error("unset copilot_tokens should be refused")
+	}
+	if after := readUserConfig(t, home); after != before {
+		t.Errorf("a refused unset must keep the config unchanged:\n%s", after)
+	}
+
+	if out, err := runCCC(t, home, "unset", "mcp"); err != nil || strings.Contains(out, "ghp_nestedsecret") {
+		t.Errorf("unset mcp = %v\n%s", err, out)
+	}
+	if after := readUserConfig(t, home); strings.Contains(after, "ghp_nestedsecret") {
+		t.Errorf("unset should remove the entry holding the token:\n%s", after)
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+	"github.com/jsburckhardt/co-config/internal/copilot"
+	"github.com/jsburckhardt/co-config/internal/sensitive"
+)
+
+// sensitiveCategory groups sensitive keys, matching the TUI.
+const sensitiveCategory = "Sensitive"
+
+// schemaRecord describes one config field as reported by `copilot help config`.
+type schemaRecord struct {
+	Name        string   `json:"name" yaml:"name"`
+	Type        string   `json:"type" yaml:"type"`
+	Default     string   `json:"default,omitempty" yaml:"default,omitempty"`
+	Options     []string `json:"options,omitempty" yaml:"options,omitempty"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Category    string   `json:"category" yaml:"category"`
+}
+
+// listRecord is a schema field (or a key the schema does not list) together
+// with its current value in the selected scope. Sensitive values are masked.
+type listRecord struct {
+	schemaRecord `yaml:",inline"`
+	Value        any  `json:"value,omitempty" yaml:"value,omitempty"`
+	Set          bool `json:"set" yaml:"set"`
+	Masked       bool `json:"masked,omitempty" yaml:"masked,omitempty"`
+	Unlisted     bool `json:"unlisted,omitempty" yaml:"unlisted,omitempty"`
+}
+
+// envRecord describes one environment variable and whether it is set.
+type envRecord struct {
+	Name        string   `json:"name" yaml:"name"`
+	Aliases     []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Qualifier   string   `json:"qualifier,omitempty" yaml:"qualifier,omitempty"`
+	SetBy       string   `json:"set_by,omitempty" yaml:"set_by,omitempty"`
+	Value       string   `json:"value,omitempty" yaml:"value,omitempty"`
+	Masked      bool     `json:"masked,omitempty" yaml:"masked,omitempty"`
+	Overrides   string   `json:"overrides,omitempty" yaml:"overrides,omitempty"` // config key the variable overrides
+}
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List config fields with their current values in the selected scope",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runList,
+	}
+	addOutputFlag(cmd)
+	return cmd
+}
+
+func newSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "schema",
+		Short:        "Print the config schema detected from `copilot help config`",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runSchema,
+	}
+	addOutputFlag(cmd)
+	cmd.AddCommand(newSchemaDiffCmd())
+	return cmd
+}
+
+func newEnvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "env",
+		Short:        "List the environment variables Copilot reads and their current values",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runEnv,
+	}
+	addOutputFlag(cmd)
+	return cmd
+}
+
+func runSchema(cmd *cobra.Command, _ []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	records := schemaRecords(detectSchema(cmd))
+	if format != outputTable {
+		return writeStructured(cmd.OutOrStdout(), format, records)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tDEFAULT\tCATEGORY\tOPTIONS")
+	for _, r := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Name, r.Type, r.Default, r.Category, strings.Join(r.Options, ", "))
+	}
+	return w.Flush()
+}
+
+func runList(cmd *cobra.Command, _ []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	_, _, cfg, err := loadScopeConfig(cmd)
+	if err != nil {
+		return err
+	}
+	schema := detectSchema(cmd)
+
+	var records []listRecord
+	for _, sr := range schemaRecords(schema) {
+		records = append(records, newListRecord(sr, cfg.Get(sr.Name), cfg.Get(sr.Name) != nil, false))
+	}
+	var unlisted []string
+	for _, key := range cfg.Keys() {
+		if _, ok := copilot.FindField(schema, key); !ok {
+			unlisted = append(unlisted, key)
+		}
+	}
+	sort.Strings(unlisted)
+	for _, key := range unlisted {
+		sr := schemaRecord{Name: key, Type: "unknown", Category: copilot.FieldCategory(key)}
+		records = append(records, newListRecord(sr, cfg.Get(key), true, true))
+	}
+
+	if format != outputTable {
+		return writeStructured(cmd.OutOrStdout(), format, records)
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVALUE\tCATEGORY")
+	for _, r := range records {
+		value := ""
+		switch {
+		case !r.Set && r.Default != "":
+			value = r.Default + " (default)"
+		case !r.Set:
+			value = "(not set)"
+		case r.Masked:
+			value = fmt.Sprint(r.Value) // newListRecord already masked it
+		default:
+			value = maskedValue(r.Name, r.Value)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.Name, value, r.Category)
+	}
+	return w.Flush()
+}
+
+func runEnv(cmd *cobra.Command, _ []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	detected := detect(cmd)
+	envVars, err := detected.EnvVars, detected.EnvVarsErr
+	if err != nil {
+		return fmt.Errorf("detecting environment variables: %w", err)
+	}
+
+	var records []envRecord
+	for _, ev := range envVars {
+		if len(ev.Names) == 0 {
+			continue
+		}
+		r := envRecord{Name: ev.Names[0], Aliases: ev.Names[1:], Description: ev.Description, Qualifier: ev.Qualifier}
+		if field, ok := copilot.EnvVarField(ev, detected.Schema); ok {
+			r.Overrides = field.Name
+		}
+		for _, name := range ev.Names {
+			if v, ok := os.LookupEnv(name); ok {
+				r.SetBy = name
+				r.Value = v
+				if sensitive.IsEnvVarSensitive(name) || sensitive.LooksLikeToken(v) {
+					r.Value = sensitive.MaskValue(v)
+					r.Masked = true
+				}
+				break
+			}
+		}
+		records = append(records, r)
+	}
+
+	if format != outputTable {
+		return writeStructured(cmd.OutOrStdout(), format, records)
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSET BY\tVALUE\tOVERRIDES")
+	for _, r := range records {
+		name := r.Name
+		if len(r.Aliases) > 0 {
+			name += ", " + strings.Join(r.Aliases, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, r.SetBy, r.Value, r.Overrides)
+	}
+	return w.Flush()
+}
+
+// schemaRecords converts the schema to records sorted by name.
+func schemaRecords(schema []copilot.SchemaField) []schemaRecord {
+	records := make([]schemaRecord, 0, len(schema))
+	for _, f := range schema {
+		category := copilot.FieldCategory(f.Name)
+		if sensitive.IsSensitive(f.Name) {
+			category = sensitiveCategory
+		}
+		records = append(records, schemaRecord{
+			Name:        f.Name,
+			Type:        f.Type,
+			Default:     f.Default,
+			Options:     f.Options,
+			Description: f.Description,
+			Category:    category,
+		})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+	return records
+}
+
+// newListRecord attaches a value to a schema record, masking sensitive values.
+func newListRecord(sr schemaRecord, value any, set, unlisted bool) listRecord {
+	r := listRecord{schemaRecord: sr, Set: set, Unlisted: unlisted}
+	if !set {
+		return r
+	}
+	if config.IsSensitiveEntry(sr.Name, value) {
+		r.Category = sensitiveCategory
+		r.Value = sensitive.MaskValue(value)
+		r.Masked = true
+		return r
+	}
+	r.Value = value
+	return r
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jsburckhardt/co-config/internal/sensitive"
+)
+
+// UT-CLI-001: list masks sensitive values exactly once in table, JSON and YAML output
+func TestListMasksSensitiveValuesInEveryFormat(t *testing.T) {
+	const cfg = `{
+		"model": "gpt-5",
+		"copilot_tokens": {"github.com": "gho_secret"},
+		"api_key": "ghp_plain",
+		"mcp": {"servers": [{"token": "github_pat_nested"}]}
+	}`
+	want := map[string]string{
+		"model":          "gpt-5",
+		"copilot_tokens": sensitive.MaskValue(map[string]any{"github.com": "gho_secret"}),
+		"api_key":        sensitive.MaskValue("ghp_plain"),
+		"mcp":            sensitive.MaskValue(map[string]any{"servers": nil}),
+	}
+	secrets := []string{"gho_secret", "ghp_plain", "github_pat_nested"}
+
+	decoders := map[string]func([]byte, any) error{
+		outputJSON: json.Unmarshal,
+		outputYAML: yaml.Unmarshal,
+	}
+	for format, decode := range decoders {
+		t.Run(format, func(t *testing.T) {
+			out, err := runCCC(t, newCCCHome(t, cfg), "list", "-o", format)
+			if err != nil {
+				t.Fatalf("list -o %s: %v\n%s", format, err, out)
+			}
+			var records []map[string]any
+			if err := decode([]byte(out), &records); err != nil {
+				t.Fatalf("decoding %s: %v\n%s", format, err, out)
+			}
+			got := map[string]string{}
+			for _, r := range records {
+				got[r["name"].(string)], _ = r["value"].(string)
+			}
+			for name, value := range want {
+				if got[name] != value {
+					t.Errorf("%s value = %q, want %q", name, got[name], value)
+				}
+			}
+			for _, secret := range secrets {
+				if strings.Contains(out, secret) {
+					t.Errorf("output leaks %q", secret)
+				}
+			}
+		})
+	}
+
+	t.Run(outputTable, func(t *testing.T) {
+		out, err := runCCC(t, newCCCHome(t, cfg), "list")
+		if err != nil {
+			t.Fatalf("list: %v\n%s", err, out)
+		}
+		for name, value := range want {
+			found := false
+			for _, line := range strings.Split(out, "\n") {
+				if strings.HasPrefix(line, name+" ") {
+					found = strings.Contains(line, value)
+				}
+			}
+			if !found {
+				t.Errorf("no row for %s with value %q in\n%s", name, value, out)
+			}
+		}
+		for _, secret := range secrets {
+			if strings.Contains(out, secret) {
+				t.Errorf("output leaks %q", secret)
+			}
+		}
+	})
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,33 +16,54 @@ import (
 	"github.com/jsburckhardt/co-config/internal/config"
 	"github.com/jsburckhardt/co-config/internal/copilot"
 	"github.com/jsburckhardt/co-config/internal/logging"
+	"github.com/jsburckhardt/co-config/internal/schemacache"
 	"github.com/jsburckhardt/co-config/internal/tui"
 )
 
 var version = "0.1.0"
 
 func main() {
+	rootCmd := newRootCmd()
+
+	// Ctrl+C cancels a hung Copilot CLI invocation instead of killing ccc mid-write.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	_ = logging.Shutdown()
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCmd builds the ccc command with its flags and subcommands.
+func newRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
-		Use:   "ccc",
-		Short: "Copilot Config CLI — interactive TUI for GitHub Copilot CLI settings",
-		Long:  "ccc reads ~/.copilot/config.json, auto-detects the installed Copilot CLI version and available config keys, and presents them in an interactive terminal UI for editing.",
-		RunE:  run,
+		Use:              "ccc",
+		Short:            "Copilot Config CLI — interactive TUI for GitHub Copilot CLI settings",
+		Long:             "ccc reads ~/.copilot/config.json, auto-detects the installed Copilot CLI version and available config keys, and presents them in an interactive terminal UI for editing.",
+		PersistentPreRun: setupLogging,
+		RunE:             run,
 	}
 
 	rootCmd.Version = version
 	rootCmd.PersistentFlags().String("log-level", "warn", "Log level (debug, info, warn, error)")
 	rootCmd.PersistentFlags().String("scope", "user", "Config scope to edit (user, project, local)")
-
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
-	}
+	rootCmd.PersistentFlags().String("copilot-bin", "", "Copilot CLI binary to run (default $"+copilot.BinaryEnvVar+" or \"copilot\" on PATH)")
+	rootCmd.PersistentFlags().Bool("refresh-schema", false, "Detect the Copilot CLI schema again instead of using the cache")
+	rootCmd.PersistentFlags().Duration("copilot-timeout", copilot.DefaultTimeout, "Time limit for each Copilot CLI invocation (0 disables it)")
+	rootCmd.Flags().Bool("review", false, "Show pending changes against the file on disk for confirmation before each TUI save")
+
+	rootCmd.AddCommand(newGetCmd(), newSetCmd(), newUnsetCmd(), newEffectiveCmd(), newBackupCmd(),
+		newExportCmd(), newImportCmd(), newProfileCmd(),
+		newListCmd(), newSchemaCmd(), newEnvCmd(), newCacheCmd(), newMigrateCmd(), newDoctorCmd(), newMoveCmd())
+	return rootCmd
 }
 
-func run(cmd *cobra.Command, args []string) error {
+// setupLogging initializes file logging for every command.
+func setupLogging(cmd *cobra.Command, _ []string) {
 	// Set slog to discard before Init to avoid breaking Bubbletea if logging.Init fails
 	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
 
-	// Initialize logging
 	logLevel, _ := cmd.Flags().GetString("log-level")
 	// Support CCC_LOG_LEVEL environment variable
 	if envLevel := os.Getenv("CCC_LOG_LEVEL"); envLevel != "" && !cmd.Flags().Changed("log-level") {
@@ -51,11 +74,13 @@ func run(cmd *cobra.Command, args []string) error {
 	if err := logging.Init(logging.ParseLevel(logLevel), logPath); err != nil {
 		slog.Warn("failed to initialize logging", "error", err)
 	}
-	defer func() { _ = logging.Shutdown() }()
-	slog.Info("ccc starting", "version", version)
+	slog.Info("ccc starting", "version", version, "command", cmd.Name())
+}
 
-	// Detect copilot version
-	copilotVersion, err := copilot.DetectVersion()
+func run(cmd *cobra.Command, args []string) error {
+	// Detect copilot version, schema and environment variables
+	detected := detect(cmd)
+	copilotVersion, err := detected.Version, detected.VersionErr
 	if err != nil {
 		if errors.Is(err, copilot.ErrCopilotNotInstalled) {
 			fmt.Fprintln(os.Stderr, "Error: GitHub Copilot CLI is not installed.")
@@ -66,50 +91,46 @@ func run(cmd *cobra.Command, args []string) error {
 	}
 	slog.Info("detected copilot version", "version", copilotVersion)
 
-	// Detect config schema
-	schema, err := copilot.DetectSchema()
-	if err != nil {
-		slog.Warn("failed to detect config schema, using empty schema", "error", err)
-		schema = []copilot.SchemaField{}
-	}
-	slog.Info("detected config schema", "fields", len(schema))
+	schema := schemaOrEmpty(detected)
 
-	// Detect environment variables
-	envVars, err := copilot.DetectEnvVars()
+	envVars, err := detected.EnvVars, detected.EnvVarsErr
 	if err != nil {
 		slog.Warn("failed to detect environment variables, using empty list", "error", err)
 		envVars = []copilot.EnvVarInfo{}
 	}
 	slog.Info("detected environment variables", "count", len(envVars))
 
-	// Parse --scope flag
-	scopeStr, _ := cmd.Flags().GetString("scope")
-	scope, err := config.ParseScope(scopeStr)
-	if err != nil {
-		return fmt.Errorf("invalid --scope flag: %w", err)
-	}
-
-	// Resolve project directory (used for project and local scopes)
-	projectDir, err := os.Getwd()
+	scope, projectDir, err := resolveScope(cmd)
 	if err != nil {
-		return fmt.Errorf("getting working directory: %w", err)
+		return err
 	}
 
 	// Load config
 	configPath := config.ScopePathFor(scope, projectDir)
-	cfg, err := config.LoadConfig(configPath)
+	cfg, err := loadConfigOrEmpty(configPath)
 	if err != nil {
-		if errors.Is(err, config.ErrConfigNotFound) {
-			slog.Info("config file not found, starting with empty config", "path", configPath)
-			cfg = config.NewConfig()
-		} else {
-			return fmt.Errorf("loading config: %w", err)
-		}
+		return err
 	}
-	slog.Info("loaded config", "path", configPath, "keys", len(cfg.Keys()))
 
 	// Build and run TUI with alt-screen mode
 	model := tui.NewModel(cfg, schema, envVars, copilotVersion, configPath, scope, projectDir)
+	model.WatchFiles(tui.DefaultWatchInterval)
+	review, _ := cmd.Flags().GetBool("review")
+	model.SetReviewBeforeSave(review)
+	model.SetMigrations(config.DefaultMigrations)
+	if detected.VersionErr == nil && detected.SchemaErr == nil {
+		// First TUI run with this Copilot CLI version, whichever command cached
+		// it: report what changed since the previous one.
+		store := schemacache.NewStore(schemacache.DefaultDir())
+		if e, err := store.Load(copilotVersion); err == nil && !e.DiffAcknowledged {
+			if prev, err := store.Previous(copilotVersion); err == nil {
+				model.SetSchemaChanges(prev.Version, copilot.DiffSchema(prev.Schema, schema))
+			}
+			if err := store.Acknowledge(copilotVersion); err != nil {
+				slog.Warn("failed to record schema changes as shown", "error", err)
+			}
+		}
+	}
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("running TUI: %w", err)
@@ -117,3 +138,68 @@ func run(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// copilotRunner builds the Copilot CLI runner from --copilot-bin (or
+// CCC_COPILOT_BIN) and --copilot-timeout.
+func copilotRunner(cmd *cobra.Command) copilot.Runner {
+	binary, _ := cmd.Flags().GetString("copilot-bin")
+	if envBin := os.Getenv(copilot.BinaryEnvVar); envBin != "" && !cmd.Flags().Changed("copilot-bin") {
+		binary = envBin
+	}
+	timeout, _ := cmd.Flags().GetDuration("copilot-timeout")
+	return copilot.NewExecRunner(binary, timeout)
+}
+
+// detect runs the Copilot CLI detections, reusing the schema cache unless
+// --refresh-schema is set.
+func detect(cmd *cobra.Command) schemacache.Result {
+	refresh, _ := cmd.Flags().GetBool("refresh-schema")
+	store := schemacache.NewStore(schemacache.DefaultDir())
+	return schemacache.Detect(cmd.Context(), copilotRunner(cmd), store, refresh)
+}
+
+// detectSchema detects the config schema, falling back to an empty schema on failure.
+func detectSchema(cmd *cobra.Command) []copilot.SchemaField {
+	return schemaOrEmpty(detect(cmd))
+}
+
+// schemaOrEmpty returns the detected schema, or an empty schema if detection failed.
+func schemaOrEmpty(detected schemacache.Result) []copilot.SchemaField {
+	schema, err := detected.Schema, detected.SchemaErr
+	if err != nil {
+		slog.Warn("failed to detect config schema, using empty schema", "error", err)
+		schema = []copilot.SchemaField{}
+	}
+	slog.Info("detected config schema", "fields", len(schema))
+	return schema
+}
+
+// resolveScope parses the --scope flag and resolves the project directory
+// (the current working directory) used for project and local scopes.
+func resolveScope(cmd *cobra.Command) (config.Scope, string, error) {
+	scopeStr, _ := cmd.Flags().GetString("scope")
+	scope, err := config.ParseScope(scopeStr)
+	if err != nil {
+		return config.ScopeUser, "", fmt.Errorf("invalid --scope flag: %w", err)
+	}
+
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return config.ScopeUser, "", fmt.Errorf("getting working directory: %w", err)
+	}
+	return scope, projectDir, nil
+}
+
+// loadConfigOrEmpty loads the config at path, returning an empty config if the file does not exist.
+func loadConfigOrEmpty(path string) (*config.Config, error) {
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		if errors.Is(err, config.ErrConfigNotFound) {
+			slog.Info("config file not found, starting with empty config", "path", path)
+			return config.NewConfig(), nil
+		}
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	slog.Info("loaded config", "path", path, "keys", len(cfg.Keys()))
+	return cfg, nil
+}
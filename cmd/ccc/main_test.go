@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newCCCHome creates an isolated config home holding configJSON as the user
+// config and a fake copilot binary that prints the recorded testdata.
+func newCCCHome(t *testing.T, configJSON string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("CCC_LOG_LEVEL", "")
+	if err := os.MkdirAll(filepath.Join(home, "copilot"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, "copilot", "config.json"), []byte(configJSON), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testdata, err := filepath.Abs(filepath.Join("..", "..", "internal", "copilot", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := fmt.Sprintf(`#!/bin/sh
+case "$*" in
+version) cat %[1]q/copilot-version.txt ;;
+"help config") cat %[1]q/copilot-help-config.txt ;;
+"help environment") cat %[1]q/copilot-help-environment.txt ;;
+*) exit 1 ;;
+esac
+`, testdata)
+	if err := os.WriteFile(filepath.Join(home, "copilot-fake"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	return home
+}
+
+// runCCC runs ccc with args against the config home and returns its output.
+func runCCC(t *testing.T, home string, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := newRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(append(args, "--copilot-bin", filepath.Join(home, "copilot-fake")))
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+// readUserConfig returns the raw user config file of the config home.
+func readUserConfig(t *testing.T, home string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(home, "copilot", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
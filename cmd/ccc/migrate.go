@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+)
+
+func newMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate deprecated keys in the selected scope's config file",
+		Long: "Apply ccc's migration rules for deprecated keys to the selected scope's config file. Rules can " +
+			"rename keys, move keys into a dotted namespace such as ide.*, rewrite values and drop keys; a rule " +
+			"is only shipped once a Copilot CLI release is known to need it, and none is shipped yet. " +
+			"Use --dry-run to only show the planned changes.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runMigrate,
+	}
+	cmd.Flags().Bool("dry-run", false, "Show the planned changes without writing them")
+	return cmd
+}
+
+func runMigrate(cmd *cobra.Command, _ []string) error {
+	scope, path, cfg, err := loadScopeConfig(cmd)
+	if err != nil {
+		return err
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	out := cmd.OutOrStdout()
+	steps := config.PlanMigrations(cfg, config.DefaultMigrations)
+	if len(steps) == 0 {
+		fmt.Fprintf(out, "Nothing to migrate in %s\n", path)
+		return nil
+	}
+	for _, s := range steps {
+		fmt.Fprintln(out, s.String())
+	}
+	if dryRun {
+		fmt.Fprintf(out, "Dry run: %d migration(s) not applied to %s\n", len(steps), path)
+		return nil
+	}
+
+	config.Migrate(cfg, config.DefaultMigrations)
+	if err := saveScopeConfig(scope, path, cfg); err != nil {
+		return err
+	}
+	slog.Info("config migrated", "path", path, "steps", len(steps))
+	fmt.Fprintf(out, "Applied %d migration(s) to %s\n", len(steps), path)
+	return nil
+}
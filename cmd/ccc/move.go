@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+)
+
+func newMoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "move <key> --from <scope> --to <scope>",
+		Short: "Move a config key between scopes",
+		Long: "Move a config key from one scope's file to another, for example from settings.local.json " +
+			"to the shared project settings.json. Both files are written atomically, the target first. " +
+			"Use --copy to keep the key in the source scope. Sensitive values are never moved into project settings.",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runMove,
+	}
+	cmd.Flags().String("from", "", "Scope to move the key from (user, project, local)")
+	cmd.Flags().String("to", "", "Scope to move the key to (user, project, local)")
+	cmd.Flags().Bool("copy", false, "Copy the key instead of moving it")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+	return cmd
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	fromStr, _ := cmd.Flags().GetString("from")
+	toStr, _ := cmd.Flags().GetString("to")
+	keep, _ := cmd.Flags().GetBool("copy")
+	from, err := config.ParseScope(fromStr)
+	if err != nil {
+		return fmt.Errorf("invalid --from flag: %w", err)
+	}
+	to, err := config.ParseScope(toStr)
+	if err != nil {
+		return fmt.Errorf("invalid --to flag: %w", err)
+	}
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting working directory: %w", err)
+	}
+
+	if err := config.MoveKey(key, from, to, projectDir, keep); err != nil {
+		return sharedHint(err)
+	}
+	verb := "Moved"
+	if keep {
+		verb = "Copied"
+	}
+	slog.Info("config key moved", "key", key, "from", from.String(), "to", to.String(), "copy", keep)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s %s from %s to %s\n", verb, key, config.ScopePathFor(from, projectDir), config.ScopePathFor(to, projectDir))
+	return nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UT-CLI-008: move refuses to put a token into project settings and suggests the local scope
+func TestMoveSuggestsLocalScope(t *testing.T) {
+	home := newCCCHome(t, `{"theme": "dark", "mcp": {"env": {"TOKEN": "ghp_nestedsecret"}}}`)
+	project := t.TempDir()
+	t.Chdir(project)
+
+	out, err := runCCC(t, home, "move", "mcp", "--from", "user", "--to", "project")
+	if err == nil || !strings.Contains(err.Error(), "settings.local.json") {
+		t.Errorf("move mcp = %v, want the settings.local.json hint\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(project, ".copilot", "settings.json")); !os.IsNotExist(err) {
+		t.Error("a refused move must not write project settings")
+	}
+
+	if out, err := runCCC(t, home, "move", "theme", "--from", "user", "--to", "project"); err != nil {
+		t.Errorf("move theme: %v\n%s", err, out)
+	}
+}
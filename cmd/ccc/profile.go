@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsburckhardt/co-config/internal/bundle"
+	"github.com/jsburckhardt/co-config/internal/copilot"
+	"github.com/jsburckhardt/co-config/internal/profile"
+	"github.com/jsburckhardt/co-config/internal/validate"
+)
+
+func newProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Save and switch between named sets of settings",
+		Long: "Profiles snapshot non-sensitive settings (for example model, reasoning_effort and streamer_mode) " +
+			"so they can be applied to any scope with one command. They are stored in " + profile.DefaultDir() + ".",
+	}
+
+	save := &cobra.Command{
+		Use:          "save <name>",
+		Short:        "Snapshot the selected scope's settings as a profile",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runProfileSave,
+	}
+	save.Flags().StringSlice("keys", nil, "Only capture these keys")
+	save.Flags().StringSlice("category", nil, fmt.Sprintf("Only capture keys in these categories (%s)", strings.Join(copilot.Categories, ", ")))
+
+	cmd.AddCommand(save, &cobra.Command{
+		Use:          "apply <name>",
+		Short:        "Apply a profile to the selected scope",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runProfileApply,
+	}, &cobra.Command{
+		Use:          "list",
+		Short:        "List saved profiles",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runProfileList,
+	}, &cobra.Command{
+		Use:          "delete <name>",
+		Short:        "Delete a saved profile",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runProfileDelete,
+	}, &cobra.Command{
+		Use:          "diff <name>",
+		Short:        "Show what applying a profile would change in the selected scope",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runProfileDiff,
+	})
+	return cmd
+}
+
+func runProfileSave(cmd *cobra.Command, args []string) error {
+	_, path, cfg, err := loadScopeConfig(cmd)
+	if err != nil {
+		return err
+	}
+	keys, _ := cmd.Flags().GetStringSlice("keys")
+	categories, _ := cmd.Flags().GetStringSlice("category")
+	if err := checkCategories(categories); err != nil {
+		return err
+	}
+
+	p, stripped, err := profile.Snapshot(args[0], cfg, bundle.Filter{Keys: keys, Categories: categories})
+	for _, key := range stripped {
+		fmt.Fprintf(cmd.ErrOrStderr(), "skipped sensitive key %q\n", key)
+	}
+	if err != nil {
+		return err
+	}
+	if err := profile.NewStore(profile.DefaultDir()).Save(p); err != nil {
+		return err
+	}
+	slog.Info("profile saved", "name", p.Name, "path", path, "keys", len(p.Bundle.Settings))
+	fmt.Fprintf(cmd.OutOrStdout(), "Saved profile %q with %s\n", p.Name, strings.Join(p.Keys(), ", "))
+	return nil
+}
+
+// loadCheckedProfile loads the named profile as apply uses it: trusted
+// folders missing on this machine are skipped with a warning and the
+// remaining settings are validated against the schema.
+func loadCheckedProfile(cmd *cobra.Command, name string) (*profile.Profile, error) {
+	p, err := profile.NewStore(profile.DefaultDir()).Load(name)
+	if err != nil {
+		return nil, err
+	}
+	printSkippedFolders(cmd, p.Bundle.SkipMissingFolders())
+	if err := validate.Settings(detectSchema(cmd), p.Bundle.Settings); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func runProfileApply(cmd *cobra.Command, args []string) error {
+	p, err := loadCheckedProfile(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	scope, path, cfg, err := loadScopeConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	changes := p.Apply(cfg)
+	printChanges(cmd, changes)
+	if len(changes) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s already matches profile %q\n", path, p.Name)
+		return nil
+	}
+	if err := saveScopeConfig(scope, path, cfg); err != nil {
+		return err
+	}
+	slog.Info("profile applied", "name", p.Name, "path", path, "changes", len(changes))
+	fmt.Fprintf(cmd.OutOrStdout(), "Applied profile %q to %s\n", p.Name, path)
+	return nil
+}
+
+func runProfileList(cmd *cobra.Command, _ []string) error {
+	profiles, err := profile.NewStore(profile.DefaultDir()).List()
+	if err != nil {
+		return err
+	}
+	if len(profiles) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No profiles saved yet — create one with `ccc profile save <name>`")
+		return nil
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tKEYS")
+	for _, p := range profiles {
+		fmt.Fprintf(w, "%s\t%s\n", p.Name, strings.Join(p.Keys(), ", "))
+	}
+	return w.Flush()
+}
+
+func runProfileDelete(cmd *cobra.Command, args []string) error {
+	if err := profile.NewStore(profile.DefaultDir()).Delete(args[0]); err != nil {
+		return err
+	}
+	slog.Info("profile deleted", "name", args[0])
+	fmt.Fprintf(cmd.OutOrStdout(), "Deleted profile %q\n", args[0])
+	return nil
+}
+
+func runProfileDiff(cmd *cobra.Command, args []string) error {
+	p, err := loadCheckedProfile(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	_, path, cfg, err := loadScopeConfig(cmd)
+	if err != nil {
+		return err
+	}
+	changes := p.Diff(cfg)
+	if len(changes) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s already matches profile %q\n", path, p.Name)
+		return nil
+	}
+	printChanges(cmd, changes)
+	return nil
+}
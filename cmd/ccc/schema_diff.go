@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsburckhardt/co-config/internal/copilot"
+	"github.com/jsburckhardt/co-config/internal/schemacache"
+)
+
+// fieldChangeRecord describes how one field changed between two schemas.
+type fieldChangeRecord struct {
+	Name           string   `json:"name" yaml:"name"`
+	OldType        string   `json:"old_type,omitempty" yaml:"old_type,omitempty"`
+	NewType        string   `json:"new_type,omitempty" yaml:"new_type,omitempty"`
+	OldDefault     string   `json:"old_default,omitempty" yaml:"old_default,omitempty"`
+	NewDefault     string   `json:"new_default,omitempty" yaml:"new_default,omitempty"`
+	AddedOptions   []string `json:"added_options,omitempty" yaml:"added_options,omitempty"`
+	RemovedOptions []string `json:"removed_options,omitempty" yaml:"removed_options,omitempty"`
+}
+
+// schemaDiffRecord is the difference between the schemas of two Copilot CLI
+// versions, plus the keys set in the selected scope that are now obsolete.
+type schemaDiffRecord struct {
+	From     string              `json:"from" yaml:"from"`
+	To       string              `json:"to" yaml:"to"`
+	Added    []schemaRecord      `json:"added" yaml:"added"`
+	Removed  []schemaRecord      `json:"removed" yaml:"removed"`
+	Changed  []fieldChangeRecord `json:"changed" yaml:"changed"`
+	Obsolete []string            `json:"obsolete" yaml:"obsolete"`
+}
+
+func newSchemaDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [from-version] [to-version]",
+		Short: "Compare the config schema between Copilot CLI versions",
+		Long: "Compare the config schema of two Copilot CLI versions from the schema cache. By default the " +
+			"installed version is compared with the newest older version in the cache. Keys set in the " +
+			"selected scope that the newer version no longer lists are reported as obsolete.",
+		Args:         cobra.MaximumNArgs(2),
+		SilenceUsage: true,
+		RunE:         runSchemaDiff,
+	}
+	addOutputFlag(cmd)
+	return cmd
+}
+
+func runSchemaDiff(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	store := schemacache.NewStore(schemacache.DefaultDir())
+
+	var to *schemacache.Entry
+	if len(args) == 2 {
+		if to, err = store.Load(args[1]); err != nil {
+			return err
+		}
+	} else {
+		detected := detect(cmd)
+		if detected.VersionErr != nil {
+			return fmt.Errorf("detecting copilot version: %w", detected.VersionErr)
+		}
+		if detected.SchemaErr != nil {
+			return fmt.Errorf("detecting config schema: %w", detected.SchemaErr)
+		}
+		to = &schemacache.Entry{Version: detected.Version, Schema: detected.Schema}
+	}
+
+	var from *schemacache.Entry
+	if len(args) >= 1 {
+		from, err = store.Load(args[0])
+	} else {
+		from, err = store.Previous(to.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("%w (the schema of each Copilot CLI version is cached the first time ccc runs with it)", err)
+	}
+
+	_, _, cfg, err := loadScopeConfig(cmd)
+	if err != nil {
+		return err
+	}
+	diff := copilot.DiffSchema(from.Schema, to.Schema)
+	record := schemaDiffRecord{
+		From:     from.Version,
+		To:       to.Version,
+		Added:    schemaRecords(diff.Added),
+		Removed:  schemaRecords(diff.Removed),
+		Changed:  []fieldChangeRecord{},
+		Obsolete: diff.Obsolete(cfg.Keys()),
+	}
+	for _, c := range diff.Changed {
+		r := fieldChangeRecord{Name: c.Name, AddedOptions: c.AddedOptions, RemovedOptions: c.RemovedOptions}
+		if c.TypeChanged() {
+			r.OldType, r.NewType = c.OldType, c.NewType
+		}
+		if c.DefaultChanged() {
+			r.OldDefault, r.NewDefault = c.OldDefault, c.NewDefault
+		}
+		record.Changed = append(record.Changed, r)
+	}
+	if record.Obsolete == nil {
+		record.Obsolete = []string{}
+	}
+
+	if format != outputTable {
+		return writeStructured(cmd.OutOrStdout(), format, record)
+	}
+	printSchemaDiff(cmd, record)
+	return nil
+}
+
+// printSchemaDiff prints the diff as +/-/~ lines.
+func printSchemaDiff(cmd *cobra.Command, r schemaDiffRecord) {
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Copilot CLI %s → %s\n", r.From, r.To)
+	if len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0 {
+		fmt.Fprintln(out, "No schema changes")
+	}
+	for _, f := range r.Added {
+		fmt.Fprintf(out, "+ %s (%s)\n", f.Name, f.Type)
+	}
+	for _, f := range r.Removed {
+		fmt.Fprintf(out, "- %s (%s)\n", f.Name, f.Type)
+	}
+	for _, c := range r.Changed {
+		var parts []string
+		if c.OldType != c.NewType {
+			parts = append(parts, fmt.Sprintf("type %s → %s", c.OldType, c.NewType))
+		}
+		if c.OldDefault != c.NewDefault {
+			parts = append(parts, fmt.Sprintf("default %s → %s", quoteDefault(c.OldDefault), quoteDefault(c.NewDefault)))
+		}
+		for _, o := range c.AddedOptions {
+			parts = append(parts, "+"+o)
+		}
+		for _, o := range c.RemovedOptions {
+			parts = append(parts, "-"+o)
+		}
+		fmt.Fprintf(out, "~ %s: %s\n", c.Name, strings.Join(parts, ", "))
+	}
+	if len(r.Obsolete) > 0 {
+		fmt.Fprintf(out, "\nObsolete keys in the selected scope: %s\n", strings.Join(r.Obsolete, ", "))
+	}
+}
+
+// quoteDefault renders an empty default as "(none)".
+func quoteDefault(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,242 @@
+// Package bundle exports and imports portable Copilot settings bundles.
+// Bundles never carry sensitive data: keys classified by the sensitive
+// package and values holding a token at any depth are stripped on export
+// and on import.
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+	"github.com/jsburckhardt/co-config/internal/copilot"
+	"github.com/jsburckhardt/co-config/internal/validate"
+)
+
+// FormatVersion is the bundle format version written by Export.
+const FormatVersion = 1
+
+var (
+	ErrUnknownFormat = errors.New("unknown bundle format")
+	ErrInvalidBundle = errors.New("invalid settings bundle")
+	ErrUnknownMode   = errors.New("unknown import mode")
+)
+
+// Format is the serialization of a bundle file.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// ParseFormat parses "json" or "yaml" (also "yml").
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("%w %q: must be json or yaml", ErrUnknownFormat, s)
+	}
+}
+
+// FormatForPath picks the format from a file extension, defaulting to JSON.
+func FormatForPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatJSON
+	}
+}
+
+// Bundle is a portable set of non-sensitive settings.
+type Bundle struct {
+	Version  int            `json:"version" yaml:"version"`
+	Settings map[string]any `json:"settings" yaml:"settings"`
+}
+
+// Filter selects which keys are exported. An empty filter selects every key;
+// otherwise a key is selected when it is listed in Keys or its category
+// (see copilot.FieldCategory) is listed in Categories.
+type Filter struct {
+	Keys       []string
+	Categories []string
+}
+
+func (f Filter) selects(key string) bool {
+	if len(f.Keys) == 0 && len(f.Categories) == 0 {
+		return true
+	}
+	for _, k := range f.Keys {
+		if k == key {
+			return true
+		}
+	}
+	cat := copilot.FieldCategory(key)
+	for _, c := range f.Categories {
+		if strings.EqualFold(c, cat) {
+			return true
+		}
+	}
+	return false
+}
+
+// Export builds a bundle from the selected keys of cfg. Sensitive keys are
+// left out and returned, sorted, so callers can report them.
+func Export(cfg *config.Config, filter Filter) (*Bundle, []string) {
+	b := &Bundle{Version: FormatVersion, Settings: make(map[string]any)}
+	var stripped []string
+	for _, key := range cfg.Keys() {
+		if !filter.selects(key) {
+			continue
+		}
+		value := cfg.Get(key)
+		if config.IsSensitiveEntry(key, value) {
+			stripped = append(stripped, key)
+			continue
+		}
+		b.Settings[key] = value
+	}
+	sort.Strings(stripped)
+	return b, stripped
+}
+
+// Marshal serializes the bundle in the given format.
+func (b *Bundle) Marshal(format Format) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(b); err != nil {
+			return nil, fmt.Errorf("encoding bundle: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("encoding bundle: %w", err)
+		}
+		return buf.Bytes(), nil
+	case FormatJSON:
+		data, err := json.MarshalIndent(b, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encoding bundle: %w", err)
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// Parse reads a bundle in either format. Values decoded from YAML are
+// normalized to the types encoding/json produces so they round-trip into
+// config.json unchanged. Sensitive keys in the bundle are dropped and returned.
+func Parse(data []byte) (*Bundle, []string, error) {
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		var raw any
+		if yerr := yaml.Unmarshal(data, &raw); yerr != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrInvalidBundle, yerr)
+		}
+		normalized, nerr := json.Marshal(raw)
+		if nerr != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrInvalidBundle, nerr)
+		}
+		b = Bundle{}
+		if err := json.Unmarshal(normalized, &b); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrInvalidBundle, err)
+		}
+	}
+	if b.Version == 0 || b.Settings == nil {
+		return nil, nil, fmt.Errorf("%w: missing version or settings", ErrInvalidBundle)
+	}
+	if b.Version > FormatVersion {
+		return nil, nil, fmt.Errorf("%w: version %d is newer than supported version %d", ErrInvalidBundle, b.Version, FormatVersion)
+	}
+
+	var stripped []string
+	for key, value := range b.Settings {
+		if config.IsSensitiveEntry(key, value) {
+			delete(b.Settings, key)
+			stripped = append(stripped, key)
+		}
+	}
+	sort.Strings(stripped)
+	return &b, stripped, nil
+}
+
+// SkipMissingFolders removes the trusted_folders entries that do not exist on
+// this machine, as a bundle exported elsewhere names that machine's folders,
+// and returns them. The key is removed when none of its folders exist.
+func (b *Bundle) SkipMissingFolders() []string {
+	items, ok := b.Settings["trusted_folders"].([]any)
+	if !ok {
+		return nil
+	}
+	kept := []any{}
+	var skipped []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && errors.Is(validate.ExistingAbsPath(s), validate.ErrPathNotExist) {
+			skipped = append(skipped, s)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	switch {
+	case len(skipped) == 0:
+	case len(kept) == 0:
+		delete(b.Settings, "trusted_folders")
+	default:
+		b.Settings["trusted_folders"] = kept
+	}
+	return skipped
+}
+
+// Mode controls how Apply combines a bundle with an existing config.
+type Mode string
+
+const (
+	// ModeMerge sets the bundle's keys and keeps every other key.
+	ModeMerge Mode = "merge"
+	// ModeReplace removes every non-sensitive key not in the bundle first.
+	ModeReplace Mode = "replace"
+)
+
+// ParseMode parses "merge" or "replace".
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case ModeMerge, ModeReplace:
+		return Mode(s), nil
+	default:
+		return "", fmt.Errorf("%w %q: must be merge or replace", ErrUnknownMode, s)
+	}
+}
+
+// Apply writes the bundle's settings into cfg and returns the diff it caused.
+// Sensitive keys already in cfg are never removed or overwritten.
+func (b *Bundle) Apply(cfg *config.Config, mode Mode) []config.Change {
+	before := cfg.Clone()
+
+	if mode == ModeReplace {
+		for _, key := range cfg.Keys() {
+			if _, ok := b.Settings[key]; !ok && !config.IsSensitiveEntry(key, cfg.Get(key)) {
+				cfg.Delete(key)
+			}
+		}
+	}
+	for key, value := range b.Settings {
+		if config.IsSensitiveEntry(key, cfg.Get(key)) {
+			continue
+		}
+		cfg.Set(key, value)
+	}
+	return config.Diff(before, cfg)
+}
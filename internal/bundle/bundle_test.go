@@ -0,0 +1,194 @@
+package bundle
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+)
+
+func testConfig() *config.Config {
+	cfg := config.NewConfig()
+	cfg.Set("model", "claude-sonnet-4.5")
+	cfg.Set("reasoning_effort", "high")
+	cfg.Set("theme", "dark")
+	cfg.Set("allowed_urls", []any{"github.com", "*.example.com"})
+	cfg.Set("copilot_tokens", map[string]any{"https://github.com:user": "gho_secret"})
+	cfg.Set("logged_in_users", []any{map[string]any{"login": "user"}})
+	cfg.Set("custom_token", "ghp_abcdef")
+	return cfg
+}
+
+// UT-BND-001: Export strips sensitive keys and token-like values
+func TestExportStripsSensitive(t *testing.T) {
+	b, stripped := Export(testConfig(), Filter{})
+
+	if !reflect.DeepEqual(stripped, []string{"copilot_tokens", "custom_token", "logged_in_users"}) {
+		t.Errorf("stripped = %v", stripped)
+	}
+	for _, key := range stripped {
+		if _, ok := b.Settings[key]; ok {
+			t.Errorf("sensitive key %q exported", key)
+		}
+	}
+	if len(b.Settings) != 4 {
+		t.Errorf("exported %d keys, want 4: %v", len(b.Settings), b.Settings)
+	}
+}
+
+// UT-BND-002: Export selects keys and categories
+func TestExportFilter(t *testing.T) {
+	b, _ := Export(testConfig(), Filter{Keys: []string{"theme"}, Categories: []string{"model & ai"}})
+	want := map[string]any{"model": "claude-sonnet-4.5", "reasoning_effort": "high", "theme": "dark"}
+	if !reflect.DeepEqual(b.Settings, want) {
+		t.Errorf("settings = %v, want %v", b.Settings, want)
+	}
+}
+
+// UT-BND-003: JSON and YAML bundles round-trip to the same settings
+func TestMarshalParseRoundTrip(t *testing.T) {
+	b, _ := Export(testConfig(), Filter{})
+	for _, format := range []Format{FormatJSON, FormatYAML} {
+		data, err := b.Marshal(format)
+		if err != nil {
+			t.Fatalf("Marshal(%s) failed: %v", format, err)
+		}
+		parsed, stripped, err := Parse(data)
+		if err != nil {
+			t.Fatalf("Parse(%s) failed: %v", format, err)
+		}
+		if len(stripped) != 0 {
+			t.Errorf("%s: unexpected stripped keys %v", format, stripped)
+		}
+		if !reflect.DeepEqual(parsed.Settings, b.Settings) {
+			t.Errorf("%s: settings = %#v, want %#v", format, parsed.Settings, b.Settings)
+		}
+	}
+}
+
+// UT-BND-004: Parse normalizes YAML values, drops sensitive keys and rejects bad input
+func TestParse(t *testing.T) {
+	data := []byte("version: 1\nsettings:\n  mouse: true\n  count: 3\n  copilot_tokens:\n    a: b\n")
+	b, stripped, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !reflect.DeepEqual(stripped, []string{"copilot_tokens"}) {
+		t.Errorf("stripped = %v", stripped)
+	}
+	if b.Settings["count"] != 3.0 || b.Settings["mouse"] != true {
+		t.Errorf("settings not normalized to JSON types: %#v", b.Settings)
+	}
+
+	for _, bad := range []string{"not: [valid", `{"settings": {}}`, `{"version": 99, "settings": {}}`} {
+		if _, _, err := Parse([]byte(bad)); !errors.Is(err, ErrInvalidBundle) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidBundle", bad, err)
+		}
+	}
+}
+
+// UT-BND-005: Apply merges or replaces, never touching sensitive keys
+func TestApply(t *testing.T) {
+	b := &Bundle{Version: FormatVersion, Settings: map[string]any{"theme": "light", "banner": "never", "copilot_tokens": "x"}}
+
+	merged := testConfig()
+	changes := b.Apply(merged, ModeMerge)
+	if merged.Get("theme") != "light" || merged.Get("banner") != "never" || merged.Get("model") == nil {
+		t.Errorf("merge result = %v", merged.Data())
+	}
+	if _, ok := merged.Get("copilot_tokens").(map[string]any); !ok {
+		t.Error("merge must not overwrite sensitive keys")
+	}
+	if len(changes) != 2 {
+		t.Errorf("merge changes = %d, want 2", len(changes))
+	}
+
+	replaced := testConfig()
+	b.Apply(replaced, ModeReplace)
+	keys := replaced.Keys()
+	sort.Strings(keys)
+	got := strings.Join(keys, ",")
+	if got != "banner,copilot_tokens,custom_token,logged_in_users,theme" {
+		t.Errorf("replace kept keys %s", got)
+	}
+}
+
+// UT-BND-006: ParseFormat, FormatForPath and ParseMode
+func TestFormatsAndModes(t *testing.T) {
+	if f, err := ParseFormat("YML"); err != nil || f != FormatYAML {
+		t.Errorf("ParseFormat(YML) = %v, %v", f, err)
+	}
+	if _, err := ParseFormat("toml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("ParseFormat(toml) error = %v", err)
+	}
+	if FormatForPath("team.yaml") != FormatYAML || FormatForPath("team.json") != FormatJSON || FormatForPath("team") != FormatJSON {
+		t.Error("FormatForPath picked the wrong format")
+	}
+	if _, err := ParseMode("overwrite"); !errors.Is(err, ErrUnknownMode) {
+		t.Errorf("ParseMode(overwrite) error = %v", err)
+	}
+}
+
+// UT-BND-007: tokens nested in objects and lists never enter or leave through a bundle
+func TestNestedTokens(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Set("theme", "dark")
+	cfg.Set("mcp", map[string]any{"env": map[string]any{"TOKEN": "ghp_nested"}})
+	cfg.Set("hooks", []any{map[string]any{"auth": "gho_nested"}})
+
+	b, stripped := Export(cfg, Filter{})
+	if !reflect.DeepEqual(stripped, []string{"hooks", "mcp"}) || len(b.Settings) != 1 {
+		t.Errorf("Export settings = %v, stripped = %v", b.Settings, stripped)
+	}
+	data, err := b.Marshal(FormatJSON)
+	if err != nil || strings.Contains(string(data), "_nested") {
+		t.Errorf("exported bundle leaks a token: %s, %v", data, err)
+	}
+
+	parsed, stripped, err := Parse([]byte(`{"version": 1, "settings": {"theme": "light", "mcp": {"env": {"TOKEN": "ghp_other"}}}}`))
+	if err != nil || !reflect.DeepEqual(stripped, []string{"mcp"}) || parsed.Settings["mcp"] != nil {
+		t.Errorf("Parse settings = %v, stripped = %v, %v", parsed, stripped, err)
+	}
+
+	// Replace keeps entries holding nested tokens, so no change ever carries them.
+	for _, c := range parsed.Apply(cfg, ModeReplace) {
+		if c.Key == "mcp" || c.Key == "hooks" {
+			t.Errorf("replace changed %s", c.Key)
+		}
+	}
+	if cfg.Get("mcp") == nil || cfg.Get("hooks") == nil {
+		t.Errorf("replace removed entries holding tokens: %v", cfg.Data())
+	}
+	over := &Bundle{Version: FormatVersion, Settings: map[string]any{"mcp": map[string]any{}}}
+	if changes := over.Apply(cfg, ModeMerge); len(changes) != 0 {
+		t.Errorf("merge overwrote an entry holding a token: %v", changes)
+	}
+}
+
+// UT-BND-008: trusted folders missing on this machine are skipped, other entries are kept
+func TestSkipMissingFolders(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "elsewhere")
+	b := &Bundle{Version: FormatVersion, Settings: map[string]any{
+		"trusted_folders": []any{dir, missing, "relative/path"},
+	}}
+	skipped := b.SkipMissingFolders()
+	if !reflect.DeepEqual(skipped, []string{missing}) {
+		t.Errorf("skipped = %v, want [%s]", skipped, missing)
+	}
+	if got := b.Settings["trusted_folders"]; !reflect.DeepEqual(got, []any{dir, "relative/path"}) {
+		t.Errorf("trusted_folders = %v, want the existing and the invalid entry kept", got)
+	}
+
+	b = &Bundle{Version: FormatVersion, Settings: map[string]any{"trusted_folders": []any{missing}}}
+	if skipped := b.SkipMissingFolders(); len(skipped) != 1 {
+		t.Errorf("skipped = %v", skipped)
+	}
+	if _, ok := b.Settings["trusted_folders"]; ok {
+		t.Error("trusted_folders should be removed when none of its folders exist")
+	}
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxBackups is the number of timestamped backups kept for each config file.
+const MaxBackups = 10
+
+// backupTimeFormat is the timestamp layout used for backup IDs (UTC).
+const backupTimeFormat = "20060102-150405.000"
+
+// backupSuffix is the extension of backup files.
+const backupSuffix = ".bak"
+
+// Backup describes a timestamped copy of a config file taken before it was overwritten.
+type Backup struct {
+	ID   string
+	Path string
+	Time time.Time
+	Size int64
+}
+
+// backupDir returns the directory holding the backups of path. The user
+// config is backed up next to itself. Project settings live in the
+// repository's .copilot directory, so their backups are kept under the user
+// config directory instead, where they cannot be committed, in
+// ccc/backups/<hash of the file's absolute path>.
+func backupDir(path string) string {
+	dir := filepath.Dir(path)
+	name := filepath.Base(path)
+	if filepath.Base(dir) != ".copilot" || (name != "settings.json" && name != "settings.local.json") {
+		return dir
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(filepath.Dir(DefaultPath()), "ccc", "backups", hex.EncodeToString(sum[:])[:16])
+}
+
+// backupPrefix returns the file name prefix shared by all backups of path,
+// e.g. "config.json." for ~/.copilot/config.json.
+func backupPrefix(path string) string {
+	return filepath.Base(path) + "."
+}
+
+// ListBackups returns the backups of the config file at path, newest first.
+func ListBackups(path string) ([]Backup, error) {
+	dir := backupDir(path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading backup directory: %w", err)
+	}
+
+	prefix := backupPrefix(path)
+	var backups []Backup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, backupSuffix) {
+			continue
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(name, prefix), backupSuffix)
+		ts, err := time.Parse(backupTimeFormat, strings.SplitN(id, "_", 2)[0])
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{
+			ID:   id,
+			Path: filepath.Join(dir, name),
+			Time: ts,
+			Size: info.Size(),
+		})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].ID > backups[j].ID })
+	return backups, nil
+}
+
+// RestoreBackup atomically replaces the config file at path with the backup
+// identified by id. The current file is itself backed up first, so a restore
+// can be undone by restoring the newest backup.
+func RestoreBackup(path, id string) error {
+	data, err := readBackup(path, id)
+	if err != nil {
+		return err
+	}
+	return writeWithBackup(path, data)
+}
+
+// readBackup returns the content of the backup of path identified by id.
+func readBackup(path, id string) ([]byte, error) {
+	backups, err := ListBackups(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range backups {
+		if b.ID != id {
+			continue
+		}
+		data, err := os.ReadFile(b.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading backup: %w", err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrBackupNotFound, id)
+}
+
+// writeWithBackup backs up the existing file at path (if its content differs
+// from data), writes data atomically, and prunes old backups. Failing to prune
+// is only logged, since the write itself succeeded.
+func writeWithBackup(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+
+	existing, err := os.ReadFile(path) //nolint:gosec // path is user-provided config file path, not attacker-controlled
+	switch {
+	case err == nil:
+		if !bytes.Equal(existing, data) {
+			if err := createBackup(path, existing); err != nil {
+				return err
+			}
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("reading existing config file: %w", err)
+	}
+
+	if err := WriteFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+
+	// The file is written; stale backups left behind are no reason to report a failed save.
+	if err := pruneBackups(path, MaxBackups); err != nil {
+		slog.Warn("pruning backups failed", "path", path, "error", err)
+	}
+	return nil
+}
+
+// createBackup writes data to a new timestamped backup file of path; see backupDir.
+func createBackup(path string, data []byte) error {
+	dir := backupDir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("creating backup directory: %w", err)
+	}
+	id := time.Now().UTC().Format(backupTimeFormat)
+	name := filepath.Join(dir, backupPrefix(path)+id+backupSuffix)
+	for n := 1; ; n++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			break
+		}
+		name = filepath.Join(dir, fmt.Sprintf("%s%s_%d%s", backupPrefix(path), id, n, backupSuffix))
+	}
+	if err := WriteFileAtomic(name, data, 0600); err != nil {
+		return fmt.Errorf("creating backup: %w", err)
+	}
+	return nil
+}
+
+// pruneBackups removes the oldest backups of path beyond keep.
+func pruneBackups(path string, keep int) error {
+	backups, err := ListBackups(path)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i := keep; i < len(backups); i++ {
+		if err := os.Remove(backups[i].Path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WriteFileAtomic writes data to a temp file in the same directory, fsyncs it
+// and renames it over path, so readers never observe a partially written file.
+// It is shared with the other files ccc keeps, such as the schema cache.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	// Write through symlinks, e.g. a config.json managed in a dotfiles
+	// repository, instead of replacing the link with a regular file.
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	cleanup := func() { _ = os.Remove(tmpName) }
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		cleanup()
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes directory metadata so the rename survives a crash.
+// Errors are ignored: not every platform supports syncing a directory.
+func syncDir(dir string) {
+	d, err := os.Open(dir) //nolint:gosec // dir is the config file's own directory
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
@@ -85,6 +85,11 @@ func ScopePathFor(scope Scope, projectDir string) string {
 // All fields are stored in a single map to ensure round-trip fidelity.
 type Config struct {
 	data map[string]any
+
+	// base is a snapshot of data as last read from or written to disk, and
+	// disk fingerprints that file; both are used to detect external changes.
+	base map[string]any
+	disk fileState
 }
 
 // NewConfig creates an empty Config.
@@ -102,6 +107,17 @@ func (c *Config) Set(key string, value any) {
 	c.data[key] = value
 }
 
+// Delete removes a config key. Deleting a key that is not set is a no-op.
+func (c *Config) Delete(key string) {
+	delete(c.data, key)
+}
+
+// Clone returns a deep copy of the config values. The copy does not track
+// the file it was loaded from.
+func (c *Config) Clone() *Config {
+	return &Config{data: cloneMap(c.data)}
+}
+
 // Keys returns all config keys.
 func (c *Config) Keys() []string {
 	keys := make([]string, 0, len(c.data))
@@ -144,11 +160,18 @@ func LoadConfig(path string) (*Config, error) {
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrConfigInvalid, err)
 	}
+	if raw == nil {
+		raw = make(map[string]any)
+	}
 
-	return &Config{data: raw}, nil
+	cfg := &Config{data: raw}
+	cfg.markClean(path, data)
+	return cfg, nil
 }
 
 // SaveConfig writes the config to the given path as JSON with 2-space indentation.
+// The write is atomic (temp file, fsync, rename) and the previous content is
+// kept as a timestamped backup; see ListBackups.
 func SaveConfig(path string, cfg *Config) error {
 	data, err := json.MarshalIndent(cfg.data, "", "  ")
 	if err != nil {
@@ -156,13 +179,9 @@ func SaveConfig(path string, cfg *Config) error {
 	}
 	data = append(data, '\n')
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0750); err != nil {
-		return fmt.Errorf("creating config directory: %w", err)
-	}
-
-	if err := os.WriteFile(path, data, 0600); err != nil {
-		return fmt.Errorf("writing config file: %w", err)
+	if err := writeWithBackup(path, data); err != nil {
+		return err
 	}
+	cfg.markClean(path, data)
 	return nil
 }
@@ -71,6 +71,7 @@ func TestConfigRoundTripIntegration(t *testing.T) {
 
 // IT-004: Multi-scope config round-trip with project settings
 func TestProjectSettingsRoundTripIntegration(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
 	tmpDir := t.TempDir()
 	settingsPath := config.ProjectSettingsPath(tmpDir)
 
@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -524,3 +525,721 @@ func TestDefaultPath_ReturnsAbsolutePath(t *testing.T) {
 		t.Errorf("DefaultPath() = %q is not an absolute path", path)
 	}
 }
+
+// UT-CFG-021: Delete removes a key and is a no-op for missing keys
+func TestConfig_Delete(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Set("model", "gpt-5.2")
+	cfg.Set("theme", "dark")
+
+	cfg.Delete("model")
+	if got := cfg.Get("model"); got != nil {
+		t.Errorf("Get(\"model\") after Delete = %v, want nil", got)
+	}
+	if _, ok := cfg.Data()["model"]; ok {
+		t.Error("model should be removed from Data() after Delete")
+	}
+	if got := cfg.Get("theme"); got != "dark" {
+		t.Errorf("Get(\"theme\") = %v, want dark", got)
+	}
+
+	cfg.Delete("does_not_exist")
+	if len(cfg.Keys()) != 1 {
+		t.Errorf("Keys() = %v, want only theme", cfg.Keys())
+	}
+}
+
+// UT-CFG-022: Merge applies user → project → local precedence
+func TestMerge_Precedence(t *testing.T) {
+	user := NewConfig()
+	user.Set("model", "user-model")
+	user.Set("theme", "dark")
+	user.Set("beep", false)
+
+	project := NewConfig()
+	project.Set("model", "project-model")
+	project.Set("banner", "never")
+
+	local := NewConfig()
+	local.Set("model", "local-model")
+
+	got := Merge(map[Scope]*Config{ScopeUser: user, ScopeProject: project, ScopeProjectLocal: local})
+
+	want := map[string]struct {
+		value any
+		scope Scope
+	}{
+		"banner": {"never", ScopeProject},
+		"beep":   {false, ScopeUser},
+		"model":  {"local-model", ScopeProjectLocal},
+		"theme":  {"dark", ScopeUser},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Merge returned %d keys, want %d", len(got), len(want))
+	}
+	for i, ev := range got {
+		if i > 0 && got[i-1].Key >= ev.Key {
+			t.Errorf("results not sorted: %q before %q", got[i-1].Key, ev.Key)
+		}
+		w, ok := want[ev.Key]
+		if !ok {
+			t.Errorf("unexpected key %q", ev.Key)
+			continue
+		}
+		if !reflect.DeepEqual(ev.Value, w.value) || ev.Scope != w.scope {
+			t.Errorf("%s = %v from %v, want %v from %v", ev.Key, ev.Value, ev.Scope, w.value, w.scope)
+		}
+		if ev.Key == "model" && !reflect.DeepEqual(ev.Overrides, []Scope{ScopeUser, ScopeProject}) {
+			t.Errorf("model overrides = %v, want [user project]", ev.Overrides)
+		}
+	}
+}
+
+// UT-CFG-023: Merge tolerates missing and nil layers
+func TestMerge_MissingLayers(t *testing.T) {
+	local := NewConfig()
+	local.Set("stream", true)
+
+	got := Merge(map[Scope]*Config{ScopeProject: nil, ScopeProjectLocal: local})
+	if len(got) != 1 || got[0].Key != "stream" || got[0].Scope != ScopeProjectLocal {
+		t.Errorf("Merge = %+v, want only stream from local", got)
+	}
+	if len(got[0].Overrides) != 0 {
+		t.Errorf("Overrides = %v, want none", got[0].Overrides)
+	}
+}
+
+// UT-CFG-024: LoadLayers returns empty configs for missing files and errors on invalid JSON
+func TestLoadLayers(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := ScopePaths(tmpDir)
+	paths[ScopeUser] = filepath.Join(tmpDir, "user", "config.json")
+
+	project := NewConfig()
+	project.Set("model", "project-model")
+	if err := SaveConfig(paths[ScopeProject], project); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	layers, err := LoadLayers(paths)
+	if err != nil {
+		t.Fatalf("LoadLayers failed: %v", err)
+	}
+	if got := layers[ScopeProject].Get("model"); got != "project-model" {
+		t.Errorf("project model = %v, want project-model", got)
+	}
+	if len(layers[ScopeUser].Keys()) != 0 || len(layers[ScopeProjectLocal].Keys()) != 0 {
+		t.Error("missing scope files should load as empty configs")
+	}
+
+	if err := os.WriteFile(paths[ScopeProjectLocal], []byte("{bad"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := LoadLayers(paths); !errors.Is(err, ErrConfigInvalid) {
+		t.Errorf("LoadLayers error = %v, want ErrConfigInvalid", err)
+	}
+}
+
+// UT-CFG-025: SaveConfig keeps a backup of the previous content and leaves no temp files
+func TestSaveConfig_CreatesBackup(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.json")
+
+	cfg := NewConfig()
+	cfg.Set("model", "first")
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	backups, err := ListBackups(path)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 0 {
+		t.Errorf("first save should not create a backup, got %d", len(backups))
+	}
+
+	cfg.Set("model", "second")
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	// Saving identical content does not create another backup
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	backups, err = ListBackups(path)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("expected 1 backup, got %d", len(backups))
+	}
+	data, err := os.ReadFile(backups[0].Path)
+	if err != nil {
+		t.Fatalf("reading backup: %v", err)
+	}
+	if !strings.Contains(string(data), `"first"`) {
+		t.Errorf("backup should hold the previous content, got %s", data)
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("temp file %s left behind", e.Name())
+		}
+	}
+}
+
+// UT-CFG-026: Backups are pruned to MaxBackups, newest first
+func TestSaveConfig_PrunesBackups(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.json")
+
+	cfg := NewConfig()
+	for i := 0; i < MaxBackups+3; i++ {
+		cfg.Set("counter", float64(i))
+		if err := SaveConfig(path, cfg); err != nil {
+			t.Fatalf("SaveConfig failed: %v", err)
+		}
+	}
+
+	backups, err := ListBackups(path)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != MaxBackups {
+		t.Fatalf("expected %d backups, got %d", MaxBackups, len(backups))
+	}
+	for i := 1; i < len(backups); i++ {
+		if backups[i-1].ID <= backups[i].ID {
+			t.Errorf("backups not sorted newest first: %s before %s", backups[i-1].ID, backups[i].ID)
+		}
+	}
+	newest, err := os.ReadFile(backups[0].Path)
+	if err != nil {
+		t.Fatalf("reading newest backup: %v", err)
+	}
+	if !strings.Contains(string(newest), fmt.Sprintf(`"counter": %d`, MaxBackups+1)) {
+		t.Errorf("newest backup should hold the previous save, got %s", newest)
+	}
+}
+
+// UT-CFG-027: RestoreBackup restores content and backs up the replaced file
+func TestRestoreBackup(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "settings.json")
+
+	cfg := NewConfig()
+	cfg.Set("model", "old")
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	cfg.Set("model", "new")
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	backups, err := ListBackups(path)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("ListBackups = %v, %v; want 1 backup", backups, err)
+	}
+	if err := RestoreBackup(path, backups[0].ID); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+
+	restored, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if got := restored.Get("model"); got != "old" {
+		t.Errorf("model after restore = %v, want old", got)
+	}
+
+	backups, err = ListBackups(path)
+	if err != nil || len(backups) != 2 {
+		t.Fatalf("ListBackups after restore = %v, %v; want 2 backups", backups, err)
+	}
+
+	if err := RestoreBackup(path, "19990101-000000.000"); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("RestoreBackup(unknown) error = %v, want ErrBackupNotFound", err)
+	}
+}
+
+// UT-CFG-028: ListBackups ignores other scope files sharing the directory
+func TestListBackups_IgnoresOtherFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	project := ProjectSettingsPath(tmpDir)
+	local := ProjectLocalSettingsPath(tmpDir)
+
+	cfg := NewConfig()
+	for _, v := range []string{"a", "b"} {
+		cfg.Set("model", v)
+		if err := SaveConfig(local, cfg); err != nil {
+			t.Fatalf("SaveConfig failed: %v", err)
+		}
+	}
+
+	backups, err := ListBackups(project)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 0 {
+		t.Errorf("project backups = %v, want none", backups)
+	}
+	if backups, _ := ListBackups(local); len(backups) != 1 {
+		t.Errorf("local backups = %d, want 1", len(backups))
+	}
+}
+
+// UT-CFG-029: ChangedOnDisk detects external writes and is reset by SaveConfig
+func TestChangedOnDisk(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"model": "a"}`), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if changed, err := cfg.ChangedOnDisk(path); err != nil || changed {
+		t.Fatalf("ChangedOnDisk right after load = %v, %v; want false", changed, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"model": "b", "copilot_tokens": {}}`), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if changed, err := cfg.ChangedOnDisk(path); err != nil || !changed {
+		t.Fatalf("ChangedOnDisk after external write = %v, %v; want true", changed, err)
+	}
+
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	if changed, err := cfg.ChangedOnDisk(path); err != nil || changed {
+		t.Errorf("ChangedOnDisk after save = %v, %v; want false", changed, err)
+	}
+
+	// A config that was never loaded reports a change once the file exists
+	fresh := NewConfig()
+	missing := filepath.Join(tmpDir, "missing.json")
+	if changed, _ := fresh.ChangedOnDisk(missing); changed {
+		t.Error("NewConfig should not report a change for a missing file")
+	}
+	if changed, _ := fresh.ChangedOnDisk(path); !changed {
+		t.Error("NewConfig should report a change when the file exists")
+	}
+}
+
+// UT-CFG-030: MergeWithDisk keeps one-sided changes and reports conflicts
+func TestMergeWithDisk(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.json")
+	base := `{"model": "base", "theme": "dark", "beep": true, "banner": "once"}`
+	if err := os.WriteFile(path, []byte(base), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	ours, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	ours.Set("model", "ours")   // conflict
+	ours.Set("theme", "light")  // ours only
+	ours.Set("banner", "never") // same change on both sides
+
+	disk := `{"model": "theirs", "theme": "dark", "banner": "never", "copilot_tokens": {"h:u": "t"}}`
+	if err := os.WriteFile(path, []byte(disk), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	theirs, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if got := ours.ExternalChanges(theirs); !reflect.DeepEqual(got, []string{"banner", "beep", "copilot_tokens", "model"}) {
+		t.Errorf("ExternalChanges = %v", got)
+	}
+
+	merged, conflicts := MergeWithDisk(ours, theirs, true)
+	if len(conflicts) != 1 || conflicts[0].Key != "model" {
+		t.Fatalf("conflicts = %+v, want only model", conflicts)
+	}
+	want := map[string]any{
+		"model":          "ours",
+		"theme":          "light",
+		"banner":         "never",
+		"copilot_tokens": map[string]any{"h:u": "t"},
+	}
+	if !reflect.DeepEqual(merged.Data(), want) {
+		t.Errorf("merged (prefer ours) = %v, want %v", merged.Data(), want)
+	}
+	if changed, _ := merged.ChangedOnDisk(path); changed {
+		t.Error("merged config should be based on the on-disk version")
+	}
+
+	merged, _ = MergeWithDisk(ours, theirs, false)
+	if got := merged.Get("model"); got != "theirs" {
+		t.Errorf("merged (prefer theirs) model = %v, want theirs", got)
+	}
+	if _, ok := merged.Data()["beep"]; ok {
+		t.Error("key deleted on disk and untouched in memory should stay deleted")
+	}
+}
+
+// UT-CFG-031: Diff reports changed, added and removed keys with list item changes
+func TestDiff(t *testing.T) {
+	old := NewConfig()
+	old.Set("model", "gpt-4")
+	old.Set("beep", true)
+	old.Set("theme", "dark")
+	old.Set("allowed_urls", []any{"a.com", "b.com"})
+
+	updated := NewConfig()
+	updated.Set("model", "claude")
+	updated.Set("theme", "dark")
+	updated.Set("banner", "never")
+	updated.Set("allowed_urls", []any{"b.com", "c.com"})
+
+	changes := Diff(old, updated)
+	var keys []string
+	for _, c := range changes {
+		keys = append(keys, c.Key)
+	}
+	if !reflect.DeepEqual(keys, []string{"allowed_urls", "banner", "beep", "model"}) {
+		t.Fatalf("changed keys = %v", keys)
+	}
+
+	urls := changes[0]
+	if !urls.IsList() {
+		t.Error("allowed_urls change should be a list change")
+	}
+	if !reflect.DeepEqual(urls.Added, []any{"c.com"}) || !reflect.DeepEqual(urls.Removed, []any{"a.com"}) {
+		t.Errorf("added=%v removed=%v", urls.Added, urls.Removed)
+	}
+	if banner := changes[1]; banner.OldSet || !banner.NewSet {
+		t.Errorf("banner should be added: %+v", banner)
+	}
+	if beep := changes[2]; !beep.OldSet || beep.NewSet {
+		t.Errorf("beep should be removed: %+v", beep)
+	}
+	if model := changes[3]; model.Old != "gpt-4" || model.New != "claude" {
+		t.Errorf("model change = %+v", model)
+	}
+
+	if len(Diff(old, old)) != 0 {
+		t.Error("Diff of a config with itself should be empty")
+	}
+}
+
+// UT-CFG-032: Migrate renames, moves, transforms and drops keys in rule order
+func TestMigrate(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Set("old_theme", "dark")
+	cfg.Set("auto_connect", true)
+	cfg.Set("banner", "sometimes")
+	cfg.Set("ghost", 1)
+	cfg.Set("stale", "x")
+	cfg.Set("fresh", "kept")
+	cfg.Set("model", "gpt-4")
+
+	rules := []Migration{
+		RenameKey("old_theme", "theme", "renamed"),
+		MoveToNamespace("auto_connect", "ide", "moved"),
+		TransformValue("banner", func(v any) (any, bool) {
+			if v == "sometimes" {
+				return "once", true
+			}
+			return v, false
+		}, "value retired"),
+		TransformValue("model", func(v any) (any, bool) { return v, true }, "no-op"),
+		DropKey("ghost", "never read"),
+		RenameKey("stale", "fresh", "renamed"),
+		DropKey("missing", "not set"),
+	}
+
+	plan := PlanMigrations(cfg, rules)
+	if cfg.Get("old_theme") != "dark" {
+		t.Fatal("PlanMigrations must not modify the config")
+	}
+
+	steps := Migrate(cfg, rules)
+	if fmt.Sprint(plan) != fmt.Sprint(steps) {
+		t.Errorf("plan = %v, steps = %v", plan, steps)
+	}
+	want := map[string]any{"theme": "dark", "ide.auto_connect": true, "banner": "once", "fresh": "kept", "model": "gpt-4"}
+	if !reflect.DeepEqual(cfg.Data(), want) {
+		t.Errorf("migrated config = %v, want %v", cfg.Data(), want)
+	}
+
+	var got []string
+	for _, s := range steps {
+		got = append(got, s.String())
+	}
+	wantSteps := []string{
+		"rename old_theme → theme (renamed)",
+		"rename auto_connect → ide.auto_connect (moved)",
+		"rewrite banner: sometimes → once (value retired)",
+		"drop ghost (never read)",
+		"drop stale (fresh is already set) (renamed)",
+	}
+	if !reflect.DeepEqual(got, wantSteps) {
+		t.Errorf("steps = %q, want %q", got, wantSteps)
+	}
+	if len(Migrate(cfg, rules)) != 0 {
+		t.Error("migrating an already migrated config should do nothing")
+	}
+}
+
+// UT-CFG-033: MoveKey moves or copies a key between scope files and keeps tokens out of project settings
+func TestMoveKey(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	local := NewConfig()
+	local.Set("model", "gpt-5")
+	local.Set("allowed_urls", []any{"https://example.com"})
+	local.Set("mcp", map[string]any{"env": map[string]any{"TOKEN": "ghp_abc123"}})
+	if err := SaveConfig(ProjectLocalSettingsPath(dir), local); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := MoveKey("model", ScopeProjectLocal, ScopeProject, dir, false); err != nil {
+		t.Fatalf("MoveKey: %v", err)
+	}
+	if err := MoveKey("allowed_urls", ScopeProjectLocal, ScopeProject, dir, true); err != nil {
+		t.Fatalf("MoveKey with copy: %v", err)
+	}
+	project, err := LoadConfig(ProjectSettingsPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err = LoadConfig(ProjectLocalSettingsPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Get("model") != "gpt-5" || local.Get("model") != nil {
+		t.Errorf("model should move to project: project=%v local=%v", project.Data(), local.Data())
+	}
+	if project.Get("allowed_urls") == nil || local.Get("allowed_urls") == nil {
+		t.Errorf("allowed_urls should be copied: project=%v local=%v", project.Data(), local.Data())
+	}
+
+	if err := MoveKey("mcp", ScopeProjectLocal, ScopeProject, dir, false); !errors.Is(err, ErrSensitiveShared) {
+		t.Errorf("moving a nested token into project settings: err = %v, want ErrSensitiveShared", err)
+	}
+	if err := MoveKey("missing", ScopeProjectLocal, ScopeProject, dir, false); !errors.Is(err, ErrKeyNotSet) {
+		t.Errorf("moving an unset key: err = %v, want ErrKeyNotSet", err)
+	}
+	if err := MoveKey("model", ScopeProject, ScopeProject, dir, false); err == nil {
+		t.Error("moving a key onto its own scope should fail")
+	}
+	if local, _ := LoadConfig(ProjectLocalSettingsPath(dir)); local.Get("mcp") == nil {
+		t.Error("a refused move must not remove the key")
+	}
+}
+
+// UT-CFG-034: SaveScopeConfig refuses to write sensitive values into project settings only
+func TestSaveScopeConfigGuardsProjectSettings(t *testing.T) {
+	dir := t.TempDir()
+	cfg := NewConfig()
+	cfg.Set("theme", "dark")
+	cfg.Set("copilot_tokens", map[string]any{"u": "x"})
+	cfg.Set("mcp", []any{map[string]any{"token": "github_pat_123"}})
+	cfg.Set("note", "ghp is a prefix")
+
+	if got, want := SensitiveKeys(cfg), []string{"copilot_tokens", "mcp"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SensitiveKeys = %v, want %v", got, want)
+	}
+
+	err := SaveScopeConfig(ScopeProject, ProjectSettingsPath(dir), cfg)
+	if !errors.Is(err, ErrSensitiveShared) || !strings.Contains(err.Error(), "copilot_tokens, mcp") {
+		t.Errorf("err = %v, want ErrSensitiveShared naming the keys", err)
+	}
+	if _, err := os.Stat(ProjectSettingsPath(dir)); !os.IsNotExist(err) {
+		t.Error("a refused save must not create the file")
+	}
+	if err := SaveScopeConfig(ScopeProjectLocal, ProjectLocalSettingsPath(dir), cfg); err != nil {
+		t.Errorf("local settings may hold sensitive values: %v", err)
+	}
+
+	cfg.Delete("copilot_tokens")
+	cfg.Delete("mcp")
+	if err := SaveScopeConfig(ScopeProject, ProjectSettingsPath(dir), cfg); err != nil {
+		t.Errorf("saving project settings without sensitive values: %v", err)
+	}
+}
+
+// UT-CFG-035: Project settings are backed up under the user config directory, not in the repository
+func TestSaveConfig_ProjectBackupsOutsideRepository(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	projectDir := t.TempDir()
+	path := ProjectSettingsPath(projectDir)
+
+	cfg := NewConfig()
+	cfg.Set("theme", "dark")
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Set("theme", "light")
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".bak") {
+			t.Errorf("backup %s written inside the repository", e.Name())
+		}
+	}
+	backups, err := ListBackups(path)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("ListBackups = %v, %v; want 1 backup", backups, err)
+	}
+	if !strings.HasPrefix(backups[0].Path, filepath.Join(home, "copilot", "ccc", "backups")) {
+		t.Errorf("backup path = %s, want it under the user config directory", backups[0].Path)
+	}
+	if other, _ := ListBackups(ProjectLocalSettingsPath(projectDir)); len(other) != 0 {
+		t.Errorf("settings.local.json should not list settings.json backups: %v", other)
+	}
+	if err := RestoreBackup(path, backups[0].ID); err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	if restored, _ := LoadConfig(path); restored.Get("theme") != "dark" {
+		t.Errorf("restored theme = %v, want dark", restored.Get("theme"))
+	}
+}
+
+// UT-CFG-036: Diff compares lists as multisets, so a changed duplicate count is an added or removed item
+func TestDiff_ListDuplicates(t *testing.T) {
+	before := NewConfig()
+	before.Set("allowed_urls", []any{"a.com", "a.com", "b.com"})
+	after := NewConfig()
+	after.Set("allowed_urls", []any{"a.com", "b.com", "b.com"})
+
+	changes := Diff(before, after)
+	if len(changes) != 1 {
+		t.Fatalf("changes = %+v", changes)
+	}
+	if c := changes[0]; !reflect.DeepEqual(c.Added, []any{"b.com"}) || !reflect.DeepEqual(c.Removed, []any{"a.com"}) {
+		t.Errorf("added=%v removed=%v, want b.com added and a.com removed", c.Added, c.Removed)
+	}
+
+	after.Set("allowed_urls", []any{"b.com", "a.com", "a.com"})
+	if c := Diff(before, after)[0]; len(c.Added) != 0 || len(c.Removed) != 0 {
+		t.Errorf("a reorder should add and remove nothing: added=%v removed=%v", c.Added, c.Removed)
+	}
+}
+
+// UT-CFG-037: A save that wrote the file succeeds even when old backups cannot be pruned
+func TestSaveConfig_PruneFailureIsNotASaveFailure(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can remove files from a read-only directory")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := ProjectSettingsPath(t.TempDir())
+	cfg := NewConfig()
+	cfg.Set("theme", "dark")
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := backupDir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= MaxBackups; i++ {
+		name := fmt.Sprintf("%s20240101-0000%02d.000%s", backupPrefix(path), i, backupSuffix)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(dir, 0500); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(dir, 0700) })
+
+	// Same content: no new backup is needed, but pruning the extra one fails.
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Errorf("SaveConfig = %v, want nil once the file is written", err)
+	}
+	if backups, _ := ListBackups(path); len(backups) != MaxBackups+1 {
+		t.Fatalf("backups = %d, want the prune to have failed", len(backups))
+	}
+}
+
+// UT-CFG-038: RestoreScopeBackup refuses to restore sensitive values into project settings
+func TestRestoreScopeBackupGuardsProjectSettings(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := ProjectSettingsPath(t.TempDir())
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"theme": "dark", "mcp": {"env": {"TOKEN": "ghp_restored"}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := NewConfig()
+	cfg.Set("theme", "light")
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := ListBackups(path)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("ListBackups = %v, %v; want 1 backup", backups, err)
+	}
+
+	err = RestoreScopeBackup(ScopeProject, path, backups[0].ID)
+	if !errors.Is(err, ErrSensitiveShared) || !strings.Contains(err.Error(), "mcp") {
+		t.Errorf("err = %v, want ErrSensitiveShared naming mcp", err)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "ghp_restored") {
+		t.Error("a refused restore must not write the token")
+	}
+
+	if err := RestoreScopeBackup(ScopeProjectLocal, path, backups[0].ID); err != nil {
+		t.Errorf("personal scopes may restore sensitive values: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "ghp_restored") {
+		t.Error("the backup should be restored")
+	}
+}
+
+// UT-CFG-039: SaveConfig writes through a symlinked config file and keeps the link
+func TestSaveConfig_FollowsSymlink(t *testing.T) {
+	dotfiles := t.TempDir()
+	target := filepath.Join(dotfiles, "config.json")
+	if err := os.WriteFile(target, []byte(`{"theme": "dark"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.Symlink(target, path); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg.Set("theme", "light")
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("config.json should still be a symlink, got %v, %v", info, err)
+	}
+	if data, _ := os.ReadFile(target); !strings.Contains(string(data), `"light"`) {
+		t.Errorf("the link target should hold the saved config, got %s", data)
+	}
+	if changed, err := cfg.ChangedOnDisk(path); err != nil || changed {
+		t.Errorf("ChangedOnDisk after save = %v, %v; want false", changed, err)
+	}
+}
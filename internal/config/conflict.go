@@ -0,0 +1,181 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// fileState fingerprints a config file as it was last read or written.
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+}
+
+// markClean records data as the on-disk content of path and snapshots the
+// current values as the merge base.
+func (c *Config) markClean(path string, data []byte) {
+	c.base = cloneMap(c.data)
+	c.disk = fileState{exists: true, size: int64(len(data)), hash: sha256.Sum256(data)}
+	if info, err := os.Stat(path); err == nil {
+		c.disk.modTime = info.ModTime()
+	}
+}
+
+// ChangedOnDisk reports whether the file at path differs from the content this
+// config was loaded from or last saved to. Configs created with NewConfig have
+// no recorded state and report a change only if the file now exists.
+func (c *Config) ChangedOnDisk(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c.disk.exists, nil
+		}
+		return false, err
+	}
+	if !c.disk.exists {
+		return true, nil
+	}
+	if info.Size() == c.disk.size && info.ModTime().Equal(c.disk.modTime) {
+		return false, nil
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // path is user-provided config file path, not attacker-controlled
+	if err != nil {
+		return false, err
+	}
+	sum := sha256.Sum256(data)
+	return !bytes.Equal(sum[:], c.disk.hash[:]), nil
+}
+
+// Conflict is a key that was changed both in memory and on disk, to different values.
+type Conflict struct {
+	Key    string
+	Base   any
+	Ours   any
+	Theirs any
+	// OursSet and TheirsSet are false when the respective side deleted the key.
+	OursSet   bool
+	TheirsSet bool
+}
+
+// MergeWithDisk performs a three-way merge of ours (the in-memory config) and
+// theirs (freshly loaded from disk), using the state ours was loaded from as
+// the base. Keys changed on only one side take that side's value; keys
+// changed on both sides to different values are conflicts, resolved with ours
+// when preferOurs is true and with theirs otherwise. The merged config is
+// based on theirs, so saving it will not report a further disk change.
+func MergeWithDisk(ours, theirs *Config, preferOurs bool) (*Config, []Conflict) {
+	merged := &Config{data: make(map[string]any), base: cloneMap(theirs.base), disk: theirs.disk}
+
+	keys := map[string]bool{}
+	for _, m := range []map[string]any{ours.base, ours.data, theirs.data} {
+		for k := range m {
+			keys[k] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var conflicts []Conflict
+	for _, k := range sorted {
+		b, bSet := ours.base[k]
+		o, oSet := ours.data[k]
+		t, tSet := theirs.data[k]
+
+		oursChanged := !sameEntry(b, bSet, o, oSet)
+		theirsChanged := !sameEntry(b, bSet, t, tSet)
+
+		useOurs := false
+		switch {
+		case !oursChanged:
+			useOurs = false
+		case !theirsChanged || sameEntry(o, oSet, t, tSet):
+			useOurs = true
+		default:
+			conflicts = append(conflicts, Conflict{Key: k, Base: b, Ours: o, Theirs: t, OursSet: oSet, TheirsSet: tSet})
+			useOurs = preferOurs
+		}
+
+		if useOurs {
+			if oSet {
+				merged.data[k] = cloneValue(o)
+			}
+		} else if tSet {
+			merged.data[k] = cloneValue(t)
+		}
+	}
+	return merged, conflicts
+}
+
+// ChangedKeys returns the keys whose value differs between the state this
+// config was loaded from and its current in-memory data, sorted.
+func (c *Config) ChangedKeys() []string {
+	return changedKeys(c.base, c.data)
+}
+
+// ExternalChanges returns the keys whose value in theirs (a fresh load of the
+// same file) differs from the state this config was loaded from, sorted.
+func (c *Config) ExternalChanges(theirs *Config) []string {
+	return changedKeys(c.base, theirs.data)
+}
+
+// changedKeys returns the sorted keys added, removed or changed between base and data.
+func changedKeys(base, data map[string]any) []string {
+	var keys []string
+	for k, v := range data {
+		if b, ok := base[k]; !ok || !reflect.DeepEqual(b, v) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range base {
+		if _, ok := data[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sameEntry compares two optional values.
+func sameEntry(a any, aSet bool, b any, bSet bool) bool {
+	if aSet != bSet {
+		return false
+	}
+	return !aSet || reflect.DeepEqual(a, b)
+}
+
+// cloneMap deep-copies a decoded JSON object.
+func cloneMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = cloneValue(v)
+	}
+	return out
+}
+
+// cloneValue deep-copies a decoded JSON value.
+func cloneValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		return cloneMap(t)
+	case []any:
+		out := make([]any, len(t))
+		for i, item := range t {
+			out[i] = cloneValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
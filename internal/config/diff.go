@@ -0,0 +1,77 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Change describes how one key differs between two configs.
+// For keys whose old and new values are both lists, Added and Removed hold
+// the items present on only one side.
+type Change struct {
+	Key    string
+	Old    any
+	New    any
+	OldSet bool
+	NewSet bool
+
+	Added   []any
+	Removed []any
+}
+
+// IsList reports whether the change is between two list values.
+func (c Change) IsList() bool {
+	_, oldList := c.Old.([]any)
+	_, newList := c.New.([]any)
+	return c.OldSet && c.NewSet && oldList && newList
+}
+
+// Diff returns every key whose value differs between before and after, sorted by key.
+func Diff(before, after *Config) []Change {
+	keys := make(map[string]bool)
+	for k := range before.data {
+		keys[k] = true
+	}
+	for k := range after.data {
+		keys[k] = true
+	}
+
+	var changes []Change
+	for k := range keys {
+		ov, oldSet := before.data[k]
+		nv, newSet := after.data[k]
+		if oldSet == newSet && reflect.DeepEqual(ov, nv) {
+			continue
+		}
+		c := Change{Key: k, Old: ov, New: nv, OldSet: oldSet, NewSet: newSet}
+		if c.IsList() {
+			c.Added = listDifference(nv.([]any), ov.([]any))
+			c.Removed = listDifference(ov.([]any), nv.([]any))
+		}
+		changes = append(changes, c)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// listDifference returns the items of a that b does not account for, in
+// order. Lists are compared as multisets: an item that appears twice in a
+// and once in b is returned once.
+func listDifference(a, b []any) []any {
+	used := make([]bool, len(b))
+	var out []any
+	for _, item := range a {
+		found := false
+		for j, other := range b {
+			if !used[j] && reflect.DeepEqual(item, other) {
+				used[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Precedence lists the scopes from lowest to highest precedence.
+// Copilot CLI cascades user → project → project-local, so a key set in a
+// later scope overrides the same key in an earlier one. Values are replaced
+// wholesale; lists and objects are not merged element by element.
+var Precedence = []Scope{ScopeUser, ScopeProject, ScopeProjectLocal}
+
+// EffectiveValue is the value Copilot CLI will use for a key after all scopes are merged.
+type EffectiveValue struct {
+	Key   string
+	Value any
+	// Scope is the scope whose file supplied the winning value.
+	Scope Scope
+	// Overrides lists lower-precedence scopes that also set the key.
+	Overrides []Scope
+}
+
+// Merge computes the effective value for every key set in any of the given
+// layers. Missing or nil layers are treated as empty. Results are sorted by key.
+func Merge(layers map[Scope]*Config) []EffectiveValue {
+	byKey := map[string]*EffectiveValue{}
+	for _, scope := range Precedence {
+		cfg := layers[scope]
+		if cfg == nil {
+			continue
+		}
+		for key, value := range cfg.data {
+			ev, ok := byKey[key]
+			if !ok {
+				byKey[key] = &EffectiveValue{Key: key, Value: value, Scope: scope}
+				continue
+			}
+			ev.Overrides = append(ev.Overrides, ev.Scope)
+			ev.Value = value
+			ev.Scope = scope
+		}
+	}
+
+	result := make([]EffectiveValue, 0, len(byKey))
+	for _, ev := range byKey {
+		result = append(result, *ev)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// LoadLayers loads the config file for each scope in paths. A scope whose file
+// does not exist yields an empty config; any other load error is returned.
+func LoadLayers(paths map[Scope]string) (map[Scope]*Config, error) {
+	layers := make(map[Scope]*Config, len(paths))
+	for scope, path := range paths {
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			if errors.Is(err, ErrConfigNotFound) {
+				layers[scope] = NewConfig()
+				continue
+			}
+			return nil, fmt.Errorf("loading %s scope: %w", scope, err)
+		}
+		layers[scope] = cfg
+	}
+	return layers, nil
+}
+
+// ScopePaths returns the config file path of every scope for the given project directory.
+func ScopePaths(projectDir string) map[Scope]string {
+	paths := make(map[Scope]string, len(Precedence))
+	for _, scope := range Precedence {
+		paths[scope] = ScopePathFor(scope, projectDir)
+	}
+	return paths
+}
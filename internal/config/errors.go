@@ -3,6 +3,9 @@ package config
 import "errors"
 
 var (
-	ErrConfigNotFound = errors.New("copilot config file not found")
-	ErrConfigInvalid  = errors.New("copilot config file is invalid")
+	ErrConfigNotFound  = errors.New("copilot config file not found")
+	ErrConfigInvalid   = errors.New("copilot config file is invalid")
+	ErrBackupNotFound  = errors.New("config backup not found")
+	ErrKeyNotSet       = errors.New("config key is not set")
+	ErrSensitiveShared = errors.New("sensitive values cannot be stored in shared project settings")
 )
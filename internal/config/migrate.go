@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MigrationKind is what a migration rule does with a deprecated key.
+type MigrationKind int
+
+const (
+	MigrationRename    MigrationKind = iota // move the value to another key
+	MigrationTransform                      // rewrite the value in place
+	MigrationDrop                           // remove the key
+)
+
+// Migration is a declarative rule that carries an old setting forward.
+type Migration struct {
+	Kind MigrationKind
+	Key  string // the deprecated key, or the key to transform
+	To   string // rename target
+	// Transform returns the new value and whether it differs from value.
+	Transform func(value any) (any, bool)
+	Reason    string
+}
+
+// RenameKey moves the value of from to to.
+func RenameKey(from, to, reason string) Migration {
+	return Migration{Kind: MigrationRename, Key: from, To: to, Reason: reason}
+}
+
+// MoveToNamespace moves key under a dotted namespace, e.g. auto_connect → ide.auto_connect.
+func MoveToNamespace(key, namespace, reason string) Migration {
+	return RenameKey(key, namespace+"."+key, reason)
+}
+
+// TransformValue rewrites the value of key with fn.
+func TransformValue(key string, fn func(value any) (any, bool), reason string) Migration {
+	return Migration{Kind: MigrationTransform, Key: key, Transform: fn, Reason: reason}
+}
+
+// DropKey removes key.
+func DropKey(key, reason string) Migration {
+	return Migration{Kind: MigrationDrop, Key: key, Reason: reason}
+}
+
+// DefaultMigrations are the rules ccc applies to every scope. Rules run in
+// order, so later rules see the result of earlier ones. A rule belongs here
+// only once a Copilot CLI release is known to have renamed, moved or retired
+// the key, since applying it rewrites user settings; none is known yet.
+var DefaultMigrations []Migration
+
+// MigrationStep is one rule applied to a config.
+type MigrationStep struct {
+	Migration Migration
+	Old       any
+	New       any // value written to To (rename) or Key (transform); nil for drop
+	// Conflict is set when a rename target was already set: the target
+	// keeps its value and the deprecated key is removed.
+	Conflict bool
+}
+
+// String describes the step, e.g. "rename old → new".
+func (s MigrationStep) String() string {
+	m := s.Migration
+	var desc string
+	switch m.Kind {
+	case MigrationRename:
+		desc = fmt.Sprintf("rename %s → %s", m.Key, m.To)
+		if s.Conflict {
+			desc = fmt.Sprintf("drop %s (%s is already set)", m.Key, m.To)
+		}
+	case MigrationTransform:
+		desc = fmt.Sprintf("rewrite %s: %v → %v", m.Key, s.Old, s.New)
+	default:
+		desc = fmt.Sprintf("drop %s", m.Key)
+	}
+	if m.Reason != "" {
+		desc += " (" + m.Reason + ")"
+	}
+	return desc
+}
+
+// Migrate applies rules to cfg in order and returns the steps taken. Rules
+// whose key is not set, and transforms that leave the value unchanged, are
+// skipped.
+func Migrate(cfg *Config, rules []Migration) []MigrationStep {
+	var steps []MigrationStep
+	for _, m := range rules {
+		old, ok := cfg.data[m.Key]
+		if !ok {
+			continue
+		}
+		step := MigrationStep{Migration: m, Old: old}
+		switch m.Kind {
+		case MigrationRename:
+			if _, exists := cfg.data[m.To]; exists {
+				step.Conflict = true
+			} else {
+				cfg.Set(m.To, old)
+				step.New = old
+			}
+			cfg.Delete(m.Key)
+		case MigrationTransform:
+			if m.Transform == nil {
+				continue
+			}
+			value, changed := m.Transform(cloneValue(old))
+			if !changed || reflect.DeepEqual(value, old) {
+				continue
+			}
+			cfg.Set(m.Key, value)
+			step.New = value
+		case MigrationDrop:
+			cfg.Delete(m.Key)
+		default:
+			continue
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// PlanMigrations returns the steps Migrate would take without modifying cfg.
+func PlanMigrations(cfg *Config, rules []Migration) []MigrationStep {
+	return Migrate(cfg.Clone(), rules)
+}
@@ -0,0 +1,47 @@
+package config
+
+import "fmt"
+
+// MoveValue moves key from src to dst, or copies it when keep is set. to is
+// the scope of dst: sensitive values are never moved into ScopeProject,
+// whose settings.json is committed with the project.
+func MoveValue(src, dst *Config, key string, to Scope, keep bool) error {
+	value, ok := src.data[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrKeyNotSet, key)
+	}
+	if to == ScopeProject && IsSensitiveEntry(key, value) {
+		return fmt.Errorf("%w: %s", ErrSensitiveShared, key)
+	}
+	dst.Set(key, cloneValue(value))
+	if !keep {
+		src.Delete(key)
+	}
+	return nil
+}
+
+// MoveKey moves key between the files of two scopes of projectDir, or copies
+// it when keep is set. The target file is written first, so a failed write
+// leaves the key in both files rather than in neither.
+func MoveKey(key string, from, to Scope, projectDir string, keep bool) error {
+	if from == to {
+		return fmt.Errorf("cannot move %s: source and target scope are both %s", key, from)
+	}
+	paths := map[Scope]string{from: ScopePathFor(from, projectDir), to: ScopePathFor(to, projectDir)}
+	layers, err := LoadLayers(paths)
+	if err != nil {
+		return err
+	}
+	if err := MoveValue(layers[from], layers[to], key, to, keep); err != nil {
+		return err
+	}
+	if err := SaveScopeConfig(to, paths[to], layers[to]); err != nil {
+		return err
+	}
+	if keep {
+		return nil
+	}
+	// The source only loses a key, so it is saved even if it still holds
+	// other sensitive values; moving them out must stay possible.
+	return SaveConfig(paths[from], layers[from])
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jsburckhardt/co-config/internal/sensitive"
+)
+
+// IsSensitiveEntry reports whether key is a sensitive field or value holds a
+// token-like string, at any depth.
+func IsSensitiveEntry(key string, value any) bool {
+	return sensitive.IsSensitive(key) || holdsToken(value)
+}
+
+func holdsToken(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return sensitive.LooksLikeToken(v)
+	case map[string]any:
+		for _, item := range v {
+			if holdsToken(item) {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if holdsToken(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SensitiveKeys returns the sorted keys of cfg whose entries are sensitive.
+func SensitiveKeys(cfg *Config) []string {
+	var keys []string
+	for key, value := range cfg.data {
+		if IsSensitiveEntry(key, value) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CheckShared returns ErrSensitiveShared naming the sensitive keys of cfg
+// when scope is ScopeProject, whose settings.json is committed with the
+// project. Other scopes are personal and always pass.
+func CheckShared(scope Scope, cfg *Config) error {
+	if scope != ScopeProject {
+		return nil
+	}
+	if keys := SensitiveKeys(cfg); len(keys) > 0 {
+		return fmt.Errorf("%w: %s", ErrSensitiveShared, strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// SaveScopeConfig saves cfg as the file of scope at path, refusing to write
+// sensitive values into shared project settings; see CheckShared.
+func SaveScopeConfig(scope Scope, path string, cfg *Config) error {
+	if err := CheckShared(scope, cfg); err != nil {
+		return err
+	}
+	return SaveConfig(path, cfg)
+}
+
+// RestoreScopeBackup restores the backup identified by id over the file of
+// scope at path, refusing to put sensitive values back into shared project
+// settings; see CheckShared and RestoreBackup.
+func RestoreScopeBackup(scope Scope, path, id string) error {
+	data, err := readBackup(path, id)
+	if err != nil {
+		return err
+	}
+	if scope == ScopeProject {
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("%w: backup %s: %s", ErrConfigInvalid, id, err)
+		}
+		if err := CheckShared(scope, &Config{data: raw}); err != nil {
+			return err
+		}
+	}
+	return writeWithBackup(path, data)
+}
@@ -0,0 +1,58 @@
+package copilot
+
+import "strings"
+
+// categoryExact maps exact field names to their category.
+var categoryExact = map[string]string{
+	"model":            "Model & AI",
+	"reasoning_effort": "Model & AI",
+	"stream":           "Model & AI",
+	"experimental":     "Model & AI",
+
+	"theme":                 "Display",
+	"alt_screen":            "Display",
+	"render_markdown":       "Display",
+	"screen_reader":         "Display",
+	"banner":                "Display",
+	"beep":                  "Display",
+	"update_terminal_title": "Display",
+	"streamer_mode":         "Display",
+	"mouse":                 "Display",
+
+	"allowed_urls":    "URLs & Permissions",
+	"denied_urls":     "URLs & Permissions",
+	"trusted_folders": "URLs & Permissions",
+}
+
+// categoryPrefix maps field name prefixes to their category.
+// Checked only when no exact match is found.
+var categoryPrefix = map[string]string{
+	"custom_agents.": "URLs & Permissions",
+	"ide.":           "IDE Integration",
+}
+
+// Categories lists every category FieldCategory can return, in display order.
+var Categories = []string{
+	"Model & AI",
+	"Display",
+	"IDE Integration",
+	"URLs & Permissions",
+	"General",
+}
+
+// FieldCategory returns the category used to group a config field.
+func FieldCategory(name string) string {
+	if cat, ok := categoryExact[name]; ok {
+		return cat
+	}
+	bestPrefix := ""
+	for prefix := range categoryPrefix {
+		if strings.HasPrefix(name, prefix) && len(prefix) > len(bestPrefix) {
+			bestPrefix = prefix
+		}
+	}
+	if bestPrefix != "" {
+		return categoryPrefix[bestPrefix]
+	}
+	return "General"
+}
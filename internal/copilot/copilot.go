@@ -1,9 +1,11 @@
 package copilot
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os/exec"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -16,19 +18,18 @@ type SchemaField struct {
 	Description string
 }
 
-// DetectVersion runs `copilot version` and parses the version string
+// DetectVersion runs `copilot version` with DefaultRunner and parses the version string
 func DetectVersion() (string, error) {
-	// First check if copilot binary exists
-	if _, err := exec.LookPath("copilot"); err != nil {
-		return "", ErrCopilotNotInstalled
-	}
+	return DetectVersionWith(context.Background(), DefaultRunner)
+}
 
-	cmd := exec.Command("copilot", "version")
-	output, err := cmd.CombinedOutput()
+// DetectVersionWith runs `copilot version` through r and parses the version string
+func DetectVersionWith(ctx context.Context, r Runner) (string, error) {
+	output, err := r.Run(ctx, "version")
 	if err != nil {
-		return "", fmt.Errorf("failed to execute copilot version: %w", err)
+		return "", runError("version", err)
 	}
-	return ParseVersion(string(output))
+	return ParseVersion(output)
 }
 
 // ParseVersion parses the version string from copilot version output
@@ -65,19 +66,25 @@ var knownModels = []string{
 	"gpt-4.1",
 }
 
-// DetectSchema runs `copilot help config` and parses all settings into SchemaField structs
+// UsesFallbackModels reports whether the schema's model options are the
+// built-in knownModels list rather than options parsed from the CLI output.
+func UsesFallbackModels(schema []SchemaField) bool {
+	f, ok := FindField(schema, "model")
+	return ok && slices.Equal(f.Options, knownModels)
+}
+
+// DetectSchema runs `copilot help config` with DefaultRunner and parses all settings into SchemaField structs
 func DetectSchema() ([]SchemaField, error) {
-	// First check if copilot binary exists
-	if _, err := exec.LookPath("copilot"); err != nil {
-		return nil, ErrCopilotNotInstalled
-	}
+	return DetectSchemaWith(context.Background(), DefaultRunner)
+}
 
-	cmd := exec.Command("copilot", "help", "config")
-	output, err := cmd.CombinedOutput()
+// DetectSchemaWith runs `copilot help config` through r and parses all settings into SchemaField structs
+func DetectSchemaWith(ctx context.Context, r Runner) ([]SchemaField, error) {
+	output, err := r.Run(ctx, "help", "config")
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute copilot help config: %w", err)
+		return nil, runError("help config", err)
 	}
-	return ParseSchema(string(output))
+	return ParseSchema(output)
 }
 
 // ParseSchema parses the schema from copilot help config output
@@ -205,18 +212,80 @@ type EnvVarInfo struct {
 	Qualifier   string // e.g. "in order of precedence", may be empty
 }
 
-// DetectEnvVars runs `copilot help environment` and parses the output into EnvVarInfo structs
-func DetectEnvVars() ([]EnvVarInfo, error) {
-	if _, err := exec.LookPath("copilot"); err != nil {
-		return nil, ErrCopilotNotInstalled
+// EnvVarField returns the config field an environment variable overrides,
+// matching COPILOT_<NAME> against the field name (e.g. COPILOT_MODEL → model).
+func EnvVarField(ev EnvVarInfo, schema []SchemaField) (SchemaField, bool) {
+	for _, name := range ev.Names {
+		key, ok := strings.CutPrefix(name, "COPILOT_")
+		if !ok {
+			continue
+		}
+		if f, ok := FindField(schema, strings.ToLower(key)); ok {
+			return f, true
+		}
+	}
+	return SchemaField{}, false
+}
+
+// EnvOverride is a currently-set environment variable that overrides a config field.
+type EnvOverride struct {
+	Field string
+	Name  string // the variable that takes effect
+	Value string
+	// Shadowed lists other names of the same entry that are also set but
+	// lose to Name because they come later in the precedence order.
+	Shadowed []string
+}
+
+// EnvOverrides returns the override for every config field whose environment
+// variable is set, keyed by field name. When an entry lists several names
+// (aliases, or names "in order of precedence"), the first one set wins.
+func EnvOverrides(envVars []EnvVarInfo, schema []SchemaField, lookup func(string) (string, bool)) map[string]EnvOverride {
+	overrides := make(map[string]EnvOverride)
+	for _, ev := range envVars {
+		field, ok := EnvVarField(ev, schema)
+		if !ok {
+			continue
+		}
+		var o *EnvOverride
+		for _, name := range ev.Names {
+			value, set := lookup(name)
+			switch {
+			case !set:
+			case o == nil:
+				o = &EnvOverride{Field: field.Name, Name: name, Value: value}
+			default:
+				o.Shadowed = append(o.Shadowed, name)
+			}
+		}
+		if o != nil {
+			overrides[field.Name] = *o
+		}
 	}
+	return overrides
+}
+
+// DetectEnvVars runs `copilot help environment` with DefaultRunner and parses the output into EnvVarInfo structs
+func DetectEnvVars() ([]EnvVarInfo, error) {
+	return DetectEnvVarsWith(context.Background(), DefaultRunner)
+}
 
-	cmd := exec.Command("copilot", "help", "environment")
-	output, err := cmd.CombinedOutput()
+// DetectEnvVarsWith runs `copilot help environment` through r and parses the output into EnvVarInfo structs
+func DetectEnvVarsWith(ctx context.Context, r Runner) ([]EnvVarInfo, error) {
+	output, err := r.Run(ctx, "help", "environment")
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute copilot help environment: %w", err)
+		return nil, runError("help environment", err)
+	}
+	return ParseEnvVars(output)
+}
+
+// runError passes ErrCopilotNotInstalled through unchanged and wraps any
+// other runner failure with the subcommand that failed.
+func runError(subcommand string, err error) error {
+	if errors.Is(err, ErrCopilotNotInstalled) {
+		return err
 	}
-	return ParseEnvVars(string(output))
+	return fmt.Errorf("failed to execute copilot %s: %w", subcommand, err)
 }
 
 // ParseEnvVars parses the output of `copilot help environment` into EnvVarInfo structs
@@ -1,9 +1,16 @@
 package copilot
 
 import (
+	"context"
+	"errors"
 	"os"
+	"os/exec"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/jsburckhardt/co-config/internal/copilottest"
 )
 
 // UT-COP-001: ParseVersion with captured output → "0.0.412"
@@ -625,3 +632,268 @@ func TestParseEnvVarsEnabledFeatureFlags(t *testing.T) {
 		t.Errorf("Expected description to contain %q, got %q", "feature flags", entry.Description)
 	}
 }
+
+// UT-COP-021: ParseValue coerces bool values and rejects garbage
+func TestParseValueBool(t *testing.T) {
+	field := SchemaField{Name: "stream", Type: "bool"}
+
+	tests := []struct {
+		raw  string
+		want bool
+	}{
+		{"true", true}, {"on", true}, {"YES", true}, {"1", true},
+		{"false", false}, {"off", false}, {"no", false}, {"0", false},
+	}
+	for _, tt := range tests {
+		got, err := field.ParseValue(tt.raw)
+		if err != nil {
+			t.Errorf("ParseValue(%q) error: %v", tt.raw, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseValue(%q) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+
+	if _, err := field.ParseValue("maybe"); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("ParseValue(\"maybe\") error = %v, want ErrInvalidValue", err)
+	}
+}
+
+// UT-COP-022: ParseValue rejects enum values outside Options
+func TestParseValueEnum(t *testing.T) {
+	field := SchemaField{Name: "banner", Type: "enum", Options: []string{"always", "never", "once"}}
+
+	got, err := field.ParseValue("never")
+	if err != nil {
+		t.Fatalf("ParseValue(\"never\") error: %v", err)
+	}
+	if got != "never" {
+		t.Errorf("ParseValue(\"never\") = %v, want never", got)
+	}
+
+	if _, err := field.ParseValue("sometimes"); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("ParseValue(\"sometimes\") error = %v, want ErrInvalidValue", err)
+	}
+}
+
+// UT-COP-023: ParseValue accepts comma-separated and JSON array lists
+func TestParseValueList(t *testing.T) {
+	field := SchemaField{Name: "allowed_urls", Type: "list"}
+
+	got, err := field.ParseValue("github.com, *.github.com,")
+	if err != nil {
+		t.Fatalf("ParseValue error: %v", err)
+	}
+	want := []any{"github.com", "*.github.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseValue(comma list) = %v, want %v", got, want)
+	}
+
+	got, err = field.ParseValue(`["a", "b"]`)
+	if err != nil {
+		t.Fatalf("ParseValue error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []any{"a", "b"}) {
+		t.Errorf("ParseValue(JSON array) = %v, want [a b]", got)
+	}
+
+	if _, err := field.ParseValue(`[broken`); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("ParseValue(broken JSON) error = %v, want ErrInvalidValue", err)
+	}
+}
+
+// UT-COP-024: FindField locates fields by name
+func TestFindField(t *testing.T) {
+	schema := []SchemaField{{Name: "model", Type: "enum"}, {Name: "theme", Type: "enum"}}
+
+	if f, ok := FindField(schema, "theme"); !ok || f.Name != "theme" {
+		t.Errorf("FindField(theme) = %v, %v", f, ok)
+	}
+	if _, ok := FindField(schema, "missing"); ok {
+		t.Error("FindField(missing) should return false")
+	}
+}
+
+// UT-COP-025: parallel_tool_execution not in categoryExact (formerly UT-TUI-090)
+func TestParallelToolExecutionNotInCategoryExact(t *testing.T) {
+	_, ok := categoryExact["parallel_tool_execution"]
+	if ok {
+		t.Error("parallel_tool_execution should not be in categoryExact (ghost field)")
+	}
+}
+
+// UT-COP-026: FieldCategory only returns categories listed in Categories
+func TestFieldCategoryInCategories(t *testing.T) {
+	names := []string{"model", "theme", "ide.auto_connect", "custom_agents.default_local_only", "allowed_urls", "unknown_key"}
+	for _, name := range names {
+		cat := FieldCategory(name)
+		found := false
+		for _, c := range Categories {
+			if c == cat {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("FieldCategory(%q) = %q, not in Categories", name, cat)
+		}
+	}
+}
+
+// UT-COP-027: Detect*With run the expected subcommands through the runner
+func TestDetectWithFakeRunner(t *testing.T) {
+	r := copilottest.NewTestdataRunner(t)
+	ctx := context.Background()
+
+	version, err := DetectVersionWith(ctx, r)
+	if err != nil || version != "0.0.412" {
+		t.Errorf("DetectVersionWith = %q, %v; want 0.0.412", version, err)
+	}
+	schema, err := DetectSchemaWith(ctx, r)
+	if err != nil || len(schema) == 0 {
+		t.Errorf("DetectSchemaWith returned %d fields, %v", len(schema), err)
+	}
+	envVars, err := DetectEnvVarsWith(ctx, r)
+	if err != nil || len(envVars) == 0 {
+		t.Errorf("DetectEnvVarsWith returned %d vars, %v", len(envVars), err)
+	}
+
+	want := []string{"version", "help config", "help environment"}
+	if calls := r.Calls(); !reflect.DeepEqual(calls, want) {
+		t.Errorf("runner calls = %v, want %v", calls, want)
+	}
+}
+
+// UT-COP-028: runner errors keep ErrCopilotNotInstalled and context errors visible
+func TestDetectWithRunnerErrors(t *testing.T) {
+	r := &copilottest.Runner{Err: ErrCopilotNotInstalled}
+	if _, err := DetectSchemaWith(context.Background(), r); err != ErrCopilotNotInstalled {
+		t.Errorf("DetectSchemaWith error = %v, want ErrCopilotNotInstalled unwrapped", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := DetectVersionWith(ctx, copilottest.NewTestdataRunner(t))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("DetectVersionWith(cancelled) error = %v, want context.Canceled", err)
+	}
+}
+
+// UT-COP-029: ExecRunner reports a missing binary as ErrCopilotNotInstalled
+func TestExecRunnerMissingBinary(t *testing.T) {
+	r := NewExecRunner("/definitely/not/a/copilot", time.Second)
+	if _, err := r.Run(context.Background(), "version"); !errors.Is(err, ErrCopilotNotInstalled) {
+		t.Errorf("Run error = %v, want ErrCopilotNotInstalled", err)
+	}
+}
+
+// UT-COP-030: ExecRunner kills a hung binary once the timeout expires
+func TestExecRunnerTimeout(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+	r := NewExecRunner("sleep", 50*time.Millisecond)
+
+	start := time.Now()
+	_, err := r.Run(context.Background(), "10")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run took %v, want it to stop near the timeout", elapsed)
+	}
+}
+
+// UT-COP-031: DiffSchema reports added, removed and changed fields and obsolete keys
+func TestDiffSchema(t *testing.T) {
+	old := []SchemaField{
+		{Name: "model", Type: "enum", Options: []string{"a", "b"}},
+		{Name: "banner", Type: "enum", Default: "always"},
+		{Name: "legacy", Type: "bool"},
+		{Name: "theme", Type: "string", Description: "old text"},
+	}
+	new := []SchemaField{
+		{Name: "model", Type: "enum", Options: []string{"b", "c"}},
+		{Name: "banner", Type: "enum", Default: "once"},
+		{Name: "beep", Type: "bool"},
+		{Name: "theme", Type: "enum", Description: "new text"},
+	}
+
+	d := DiffSchema(old, new)
+	if len(d.Added) != 1 || d.Added[0].Name != "beep" {
+		t.Errorf("Added = %v, want [beep]", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Name != "legacy" {
+		t.Errorf("Removed = %v, want [legacy]", d.Removed)
+	}
+	if len(d.Changed) != 3 {
+		t.Fatalf("Changed = %+v, want banner, model and theme", d.Changed)
+	}
+	if c := d.Changed[0]; c.Name != "banner" || !c.DefaultChanged() || c.TypeChanged() {
+		t.Errorf("banner change = %+v", c)
+	}
+	if c := d.Changed[1]; c.Name != "model" || !reflect.DeepEqual(c.AddedOptions, []string{"c"}) || !reflect.DeepEqual(c.RemovedOptions, []string{"a"}) {
+		t.Errorf("model change = %+v", c)
+	}
+	if c := d.Changed[2]; c.Name != "theme" || !c.TypeChanged() {
+		t.Errorf("theme change = %+v", c)
+	}
+
+	if got := d.Obsolete([]string{"unknown", "legacy", "model"}); !reflect.DeepEqual(got, []string{"legacy"}) {
+		t.Errorf("Obsolete = %v, want [legacy]", got)
+	}
+	if !DiffSchema(old, old).Empty() {
+		t.Error("DiffSchema of identical schemas should be empty")
+	}
+}
+
+// UT-COP-032: EnvVarField maps COPILOT_<NAME> variables to schema fields
+func TestEnvVarField(t *testing.T) {
+	schema := []SchemaField{{Name: "model"}, {Name: "auto_update"}}
+
+	if f, ok := EnvVarField(EnvVarInfo{Names: []string{"COPILOT_AUTO_UPDATE"}}, schema); !ok || f.Name != "auto_update" {
+		t.Errorf("EnvVarField(COPILOT_AUTO_UPDATE) = %v, %v", f, ok)
+	}
+	if _, ok := EnvVarField(EnvVarInfo{Names: []string{"COPILOT_EDITOR", "VISUAL", "EDITOR"}}, schema); ok {
+		t.Error("COPILOT_EDITOR has no config field")
+	}
+	if _, ok := EnvVarField(EnvVarInfo{Names: []string{"MODEL"}}, schema); ok {
+		t.Error("only COPILOT_ variables map to config fields")
+	}
+
+	if !UsesFallbackModels([]SchemaField{{Name: "model", Options: knownModels}}) {
+		t.Error("UsesFallbackModels should detect the built-in model list")
+	}
+	if UsesFallbackModels([]SchemaField{{Name: "model", Options: []string{"gpt-4.1"}}}) {
+		t.Error("UsesFallbackModels should be false for parsed options")
+	}
+}
+
+// UT-COP-033: EnvOverrides reports the first set name and the names it shadows
+func TestEnvOverrides(t *testing.T) {
+	schema := []SchemaField{{Name: "model"}, {Name: "auto_update"}, {Name: "theme"}}
+	envVars := []EnvVarInfo{
+		{Names: []string{"COPILOT_MODEL", "COPILOT_DEFAULT_MODEL"}, Qualifier: "in order of precedence"},
+		{Names: []string{"COPILOT_AUTO_UPDATE"}},
+		{Names: []string{"COPILOT_THEME"}},
+		{Names: []string{"GH_TOKEN"}},
+	}
+	env := map[string]string{"COPILOT_MODEL": "gpt-5", "COPILOT_DEFAULT_MODEL": "gpt-4.1", "COPILOT_AUTO_UPDATE": "false", "GH_TOKEN": "x"}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	want := map[string]EnvOverride{
+		"model":       {Field: "model", Name: "COPILOT_MODEL", Value: "gpt-5", Shadowed: []string{"COPILOT_DEFAULT_MODEL"}},
+		"auto_update": {Field: "auto_update", Name: "COPILOT_AUTO_UPDATE", Value: "false"},
+	}
+	if got := EnvOverrides(envVars, schema, lookup); !reflect.DeepEqual(got, want) {
+		t.Errorf("EnvOverrides() = %v, want %v", got, want)
+	}
+
+	delete(env, "COPILOT_MODEL")
+	if got := EnvOverrides(envVars, schema, lookup)["model"]; got.Name != "COPILOT_DEFAULT_MODEL" || len(got.Shadowed) != 0 {
+		t.Errorf("lower-precedence name should apply when the first is unset, got %v", got)
+	}
+}
@@ -7,4 +7,5 @@ var (
 	ErrVersionParseFailed  = errors.New("failed to parse copilot version")
 	ErrSchemaParseFailed   = errors.New("failed to parse copilot config schema")
 	ErrEnvVarsParseFailed  = errors.New("failed to parse copilot environment variables")
+	ErrInvalidValue        = errors.New("invalid value for config field")
 )
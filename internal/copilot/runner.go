@@ -0,0 +1,70 @@
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBinary is the Copilot CLI executable looked up on PATH.
+	DefaultBinary = "copilot"
+	// DefaultTimeout bounds a single Copilot CLI invocation.
+	DefaultTimeout = 10 * time.Second
+	// BinaryEnvVar overrides DefaultBinary when set.
+	BinaryEnvVar = "CCC_COPILOT_BIN"
+)
+
+// Runner invokes the Copilot CLI with the given arguments and returns its
+// combined output.
+type Runner interface {
+	Run(ctx context.Context, args ...string) (string, error)
+}
+
+// ExecRunner runs a Copilot CLI binary as a subprocess.
+type ExecRunner struct {
+	Binary  string        // executable name or path; empty means DefaultBinary
+	Timeout time.Duration // per-invocation limit; zero or negative disables it
+}
+
+// NewExecRunner creates a runner for binary with the given timeout.
+func NewExecRunner(binary string, timeout time.Duration) *ExecRunner {
+	return &ExecRunner{Binary: binary, Timeout: timeout}
+}
+
+// Run executes the binary and returns its combined output. It returns
+// ErrCopilotNotInstalled when the binary cannot be found, and an error
+// wrapping context.DeadlineExceeded when the timeout expires.
+func (r *ExecRunner) Run(ctx context.Context, args ...string) (string, error) {
+	binary := r.Binary
+	if binary == "" {
+		binary = DefaultBinary
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrCopilotNotInstalled, binary)
+	}
+
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	// Don't wait forever on children that inherited the output pipes.
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", fmt.Errorf("running %s %s: %w", binary, strings.Join(args, " "), ctxErr)
+	}
+	if err != nil {
+		return "", fmt.Errorf("running %s %s: %w", binary, strings.Join(args, " "), err)
+	}
+	return string(output), nil
+}
+
+// DefaultRunner is used by DetectVersion, DetectSchema and DetectEnvVars.
+var DefaultRunner Runner = NewExecRunner(DefaultBinary, DefaultTimeout)
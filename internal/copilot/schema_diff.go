@@ -0,0 +1,94 @@
+package copilot
+
+import (
+	"slices"
+	"sort"
+)
+
+// FieldChange describes how a field present in two schemas changed.
+type FieldChange struct {
+	Name           string
+	OldType        string
+	NewType        string
+	OldDefault     string
+	NewDefault     string
+	AddedOptions   []string
+	RemovedOptions []string
+}
+
+// TypeChanged reports whether the field's type changed.
+func (c FieldChange) TypeChanged() bool { return c.OldType != c.NewType }
+
+// DefaultChanged reports whether the field's default changed.
+func (c FieldChange) DefaultChanged() bool { return c.OldDefault != c.NewDefault }
+
+// SchemaDiff is the difference between two schemas. All slices are sorted by name.
+type SchemaDiff struct {
+	Added   []SchemaField
+	Removed []SchemaField
+	Changed []FieldChange
+}
+
+// Empty reports whether the schemas are equivalent.
+func (d SchemaDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Obsolete returns the keys that the old schema listed but the new one no
+// longer does, sorted. Keys never listed by either schema are not reported.
+func (d SchemaDiff) Obsolete(keys []string) []string {
+	var out []string
+	for _, k := range keys {
+		if _, ok := FindField(d.Removed, k); ok {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// DiffSchema compares schema before with schema after by field name,
+// reporting added and removed fields and type, default and enum option
+// changes. Description changes are ignored.
+func DiffSchema(before, after []SchemaField) SchemaDiff {
+	var d SchemaDiff
+	for _, nf := range after {
+		of, ok := FindField(before, nf.Name)
+		if !ok {
+			d.Added = append(d.Added, nf)
+			continue
+		}
+		c := FieldChange{
+			Name:           nf.Name,
+			OldType:        of.Type,
+			NewType:        nf.Type,
+			OldDefault:     of.Default,
+			NewDefault:     nf.Default,
+			AddedOptions:   optionDifference(nf.Options, of.Options),
+			RemovedOptions: optionDifference(of.Options, nf.Options),
+		}
+		if c.TypeChanged() || c.DefaultChanged() || len(c.AddedOptions) > 0 || len(c.RemovedOptions) > 0 {
+			d.Changed = append(d.Changed, c)
+		}
+	}
+	for _, of := range before {
+		if _, ok := FindField(after, of.Name); !ok {
+			d.Removed = append(d.Removed, of)
+		}
+	}
+	sort.Slice(d.Added, func(i, j int) bool { return d.Added[i].Name < d.Added[j].Name })
+	sort.Slice(d.Removed, func(i, j int) bool { return d.Removed[i].Name < d.Removed[j].Name })
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].Name < d.Changed[j].Name })
+	return d
+}
+
+// optionDifference returns the options in a that are not in b, in order.
+func optionDifference(a, b []string) []string {
+	var out []string
+	for _, o := range a {
+		if !slices.Contains(b, o) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package copilot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseValue converts a raw command-line string into the JSON value stored
+// in config.json for this field, based on the field's Type.
+//
+//   - bool:   true/false, on/off, yes/no, 1/0 (case-insensitive)
+//   - enum:   must be one of Options (when Options are known)
+//   - list:   a JSON array, or a comma-separated list of strings
+//   - string: stored as-is
+func (f SchemaField) ParseValue(raw string) (any, error) {
+	switch f.Type {
+	case "bool":
+		switch strings.ToLower(strings.TrimSpace(raw)) {
+		case "true", "on", "yes", "1":
+			return true, nil
+		case "false", "off", "no", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%w: %s expects a boolean (true/false), got %q", ErrInvalidValue, f.Name, raw)
+	case "enum":
+		if len(f.Options) == 0 {
+			return raw, nil
+		}
+		for _, opt := range f.Options {
+			if opt == raw {
+				return raw, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %s must be one of %s, got %q", ErrInvalidValue, f.Name, strings.Join(f.Options, ", "), raw)
+	case "list":
+		return parseList(f.Name, raw)
+	default:
+		return raw, nil
+	}
+}
+
+// parseList accepts either a JSON array or a comma-separated list of strings.
+func parseList(name, raw string) ([]any, error) {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "[") {
+		var arr []any
+		if err := json.Unmarshal([]byte(trimmed), &arr); err != nil {
+			return nil, fmt.Errorf("%w: %s expects a JSON array: %s", ErrInvalidValue, name, err)
+		}
+		return arr, nil
+	}
+	result := []any{}
+	for _, part := range strings.Split(trimmed, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			result = append(result, part)
+		}
+	}
+	return result, nil
+}
+
+// FindField returns the schema field with the given name.
+func FindField(schema []SchemaField, name string) (SchemaField, bool) {
+	for _, f := range schema {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return SchemaField{}, false
+}
@@ -0,0 +1,82 @@
+// Package copilottest provides a fake Copilot CLI runner for tests. It does
+// not import the copilot package, so the copilot tests can use it too.
+package copilottest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Runner serves canned Copilot CLI output keyed by the joined arguments and
+// records the calls it receives. It is safe for concurrent use.
+type Runner struct {
+	Outputs map[string]string
+	Err     error // returned by every call when set
+
+	mu    sync.Mutex
+	calls []string
+}
+
+// Run records the call and returns the canned output for args.
+func (r *Runner) Run(ctx context.Context, args ...string) (string, error) {
+	key := strings.Join(args, " ")
+	r.mu.Lock()
+	r.calls = append(r.calls, key)
+	r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if r.Err != nil {
+		return "", r.Err
+	}
+	return r.Outputs[key], nil
+}
+
+// Calls returns the recorded calls in the order they were made.
+func (r *Runner) Calls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+// TakeCalls returns the recorded calls, sorted, and resets them. Use it when
+// the calls run concurrently.
+func (r *Runner) TakeCalls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := r.calls
+	r.calls = nil
+	sort.Strings(calls)
+	return calls
+}
+
+// NewTestdataRunner returns a runner serving the Copilot CLI output captured
+// in internal/copilot/testdata.
+func NewTestdataRunner(t testing.TB) *Runner {
+	t.Helper()
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("Failed to locate the copilot test data")
+	}
+	dir := filepath.Join(filepath.Dir(file), "..", "copilot", "testdata")
+	files := map[string]string{
+		"version":          "copilot-version.txt",
+		"help config":      "copilot-help-config.txt",
+		"help environment": "copilot-help-environment.txt",
+	}
+	r := &Runner{Outputs: make(map[string]string)}
+	for args, name := range files {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Failed to read test data: %v", err)
+		}
+		r.Outputs[args] = string(data)
+	}
+	return r
+}
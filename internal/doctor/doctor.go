@@ -0,0 +1,279 @@
+// Package doctor runs health checks over the Copilot CLI installation and the
+// config files of every scope, reporting each as pass, warn or fail.
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+	"github.com/jsburckhardt/co-config/internal/copilot"
+	"github.com/jsburckhardt/co-config/internal/schemacache"
+	"github.com/jsburckhardt/co-config/internal/sensitive"
+	"github.com/jsburckhardt/co-config/internal/validate"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check is the result of one health check.
+type Check struct {
+	Name    string   `json:"name" yaml:"name"`
+	Status  Status   `json:"status" yaml:"status"`
+	Message string   `json:"message" yaml:"message"`
+	Details []string `json:"details,omitempty" yaml:"details,omitempty"`
+}
+
+// Input is what the checks inspect.
+type Input struct {
+	Detection schemacache.Result
+	Paths     map[config.Scope]string
+	LookupEnv func(name string) (string, bool)
+}
+
+// Run performs every check and returns the results in a stable order.
+func Run(in Input) []Check {
+	checks := []Check{checkBinary(in.Detection), checkSchema(in.Detection)}
+
+	layers := make(map[config.Scope]*config.Config)
+	for _, scope := range config.Precedence {
+		path, ok := in.Paths[scope]
+		if !ok {
+			continue
+		}
+		check, cfg := checkFile(scope, path)
+		checks = append(checks, check)
+		if cfg != nil {
+			layers[scope] = cfg
+			if c, ok := checkPermissions(scope, path); ok {
+				checks = append(checks, c)
+			}
+		}
+	}
+
+	checks = append(checks,
+		checkPlaintextTokens(layers, in.Paths),
+		checkTrustedFolders(layers),
+		checkURLConflicts(layers),
+	)
+	if in.LookupEnv != nil {
+		checks = append(checks, checkEnvOverrides(in.Detection, layers, in.LookupEnv))
+	}
+	return checks
+}
+
+// Failed returns the number of failed checks.
+func Failed(checks []Check) int {
+	n := 0
+	for _, c := range checks {
+		if c.Status == StatusFail {
+			n++
+		}
+	}
+	return n
+}
+
+func checkBinary(d schemacache.Result) Check {
+	c := Check{Name: "copilot binary"}
+	switch {
+	case errors.Is(d.VersionErr, copilot.ErrCopilotNotInstalled):
+		c.Status, c.Message = StatusFail, "Copilot CLI not found; install it or point --copilot-bin at it"
+	case d.VersionErr != nil:
+		c.Status, c.Message = StatusFail, "`copilot version` failed or could not be parsed: "+d.VersionErr.Error()
+	default:
+		c.Status, c.Message = StatusPass, "Copilot CLI v"+d.Version
+	}
+	return c
+}
+
+func checkSchema(d schemacache.Result) Check {
+	c := Check{Name: "config schema"}
+	switch {
+	case d.SchemaErr != nil:
+		c.Status, c.Message = StatusFail, "`copilot help config` could not be parsed; ccc falls back to an empty schema: "+d.SchemaErr.Error()
+	case copilot.UsesFallbackModels(d.Schema):
+		c.Status, c.Message = StatusWarn, fmt.Sprintf("%d settings parsed, but the model options were not; using ccc's built-in model list", len(d.Schema))
+	default:
+		c.Status, c.Message = StatusPass, fmt.Sprintf("%d settings parsed", len(d.Schema))
+	}
+	return c
+}
+
+// checkFile loads a scope's file, returning the config when it is valid.
+// A missing file yields an empty config.
+func checkFile(scope config.Scope, path string) (Check, *config.Config) {
+	c := Check{Name: scope.String() + " config"}
+	cfg, err := config.LoadConfig(path)
+	switch {
+	case errors.Is(err, config.ErrConfigNotFound):
+		c.Status, c.Message = StatusPass, path+" does not exist"
+		return c, config.NewConfig()
+	case err != nil:
+		c.Status, c.Message = StatusFail, err.Error()
+		return c, nil
+	}
+	c.Status, c.Message = StatusPass, fmt.Sprintf("%s is valid JSON (%d keys)", path, len(cfg.Keys()))
+	return c, cfg
+}
+
+// checkPermissions reports group- or world-accessible config files; it is
+// skipped for missing files and on Windows.
+func checkPermissions(scope config.Scope, path string) (Check, bool) {
+	if runtime.GOOS == "windows" {
+		return Check{}, false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Check{}, false
+	}
+	c := Check{Name: scope.String() + " permissions"}
+	if mode := info.Mode().Perm(); mode&0077 != 0 {
+		c.Status, c.Message = StatusWarn, fmt.Sprintf("%s has mode %04o; ccc saves config files as 0600 (chmod 600 %s)", path, mode, path)
+	} else {
+		c.Status, c.Message = StatusPass, fmt.Sprintf("%s has mode %04o", path, mode)
+	}
+	return c, true
+}
+
+// checkPlaintextTokens fails when store_token_plaintext is enabled and a
+// scope's file actually holds tokens.
+func checkPlaintextTokens(layers map[config.Scope]*config.Config, paths map[config.Scope]string) Check {
+	c := Check{Name: "plaintext tokens"}
+	enabled := false
+	for _, scope := range config.Precedence {
+		cfg := layers[scope]
+		if cfg == nil {
+			continue
+		}
+		if v, _ := cfg.Get("store_token_plaintext").(bool); v {
+			enabled = true
+		}
+		if keys := tokenKeys(cfg); len(keys) > 0 {
+			c.Details = append(c.Details, fmt.Sprintf("%s: %s", paths[scope], strings.Join(keys, ", ")))
+		}
+	}
+	switch {
+	case enabled && len(c.Details) > 0:
+		c.Status, c.Message = StatusFail, "store_token_plaintext is enabled and tokens are stored in plain text"
+	case enabled:
+		c.Status, c.Message = StatusWarn, "store_token_plaintext is enabled; new tokens will be stored in plain text"
+	default:
+		c.Status, c.Message, c.Details = StatusPass, "store_token_plaintext is not enabled", nil
+	}
+	return c
+}
+
+// tokenKeys returns the keys of cfg holding credentials, sorted.
+func tokenKeys(cfg *config.Config) []string {
+	var keys []string
+	for k, v := range cfg.Data() {
+		switch {
+		case k == "copilot_tokens" && v != nil:
+			keys = append(keys, k)
+		case !sensitive.IsSensitive(k) && config.IsSensitiveEntry(k, v):
+			// Other sensitive keys hold account names; count token values at any depth.
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkTrustedFolders warns about trusted_folders entries that are not
+// existing absolute directories.
+func checkTrustedFolders(layers map[config.Scope]*config.Config) Check {
+	c := Check{Name: "trusted folders"}
+	count := 0
+	for _, scope := range config.Precedence {
+		cfg := layers[scope]
+		if cfg == nil {
+			continue
+		}
+		items, _ := cfg.Get("trusted_folders").([]any)
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok {
+				continue
+			}
+			count++
+			if err := validate.ExistingAbsPath(s); err != nil {
+				c.Details = append(c.Details, fmt.Sprintf("%s: %v", scope, err))
+			}
+		}
+	}
+	if len(c.Details) > 0 {
+		c.Status, c.Message = StatusWarn, fmt.Sprintf("%d of %d trusted folders no longer exist or are not absolute", len(c.Details), count)
+	} else {
+		c.Status, c.Message = StatusPass, fmt.Sprintf("%d trusted folders exist", count)
+	}
+	return c
+}
+
+// checkURLConflicts warns about allowed URLs that the effective denied URLs also match.
+func checkURLConflicts(layers map[config.Scope]*config.Config) Check {
+	c := Check{Name: "URL rules"}
+	effective := make(map[string]any)
+	for _, ev := range config.Merge(layers) {
+		effective[ev.Key] = ev.Value
+	}
+	allowed, _ := effective["allowed_urls"].([]any)
+	denied, _ := effective["denied_urls"].([]any)
+	c.Details = validate.URLConflicts(allowed, denied)
+	if len(c.Details) > 0 {
+		c.Status, c.Message = StatusWarn, fmt.Sprintf("%d allowed URL(s) conflict with denied URLs", len(c.Details))
+	} else {
+		c.Status, c.Message = StatusPass, fmt.Sprintf("%d allowed and %d denied URLs do not conflict", len(allowed), len(denied))
+	}
+	return c
+}
+
+// checkEnvOverrides warns about environment variables that override a key
+// set in a config file.
+func checkEnvOverrides(d schemacache.Result, layers map[config.Scope]*config.Config, lookup func(string) (string, bool)) Check {
+	c := Check{Name: "environment overrides"}
+	effective := make(map[string]config.EffectiveValue)
+	for _, ev := range config.Merge(layers) {
+		effective[ev.Key] = ev
+	}
+	overrides := copilot.EnvOverrides(d.EnvVars, d.Schema, lookup)
+	fields := make([]string, 0, len(overrides))
+	for field := range overrides {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		o := overrides[field]
+		cv, ok := effective[field]
+		if !ok {
+			continue
+		}
+		value := o.Value
+		if sensitive.IsEnvVarSensitive(o.Name) || sensitive.LooksLikeToken(value) {
+			value = sensitive.MaskValue(value)
+		}
+		// Decide on masking before formatting: fmt.Sprint prints nested tokens in full.
+		var current string
+		if config.IsSensitiveEntry(field, cv.Value) {
+			current = sensitive.MaskValue(cv.Value)
+		} else {
+			current = fmt.Sprint(cv.Value)
+		}
+		c.Details = append(c.Details, fmt.Sprintf("%s=%s overrides %s = %s (%s scope)", o.Name, value, field, current, cv.Scope))
+	}
+	if len(c.Details) > 0 {
+		c.Status, c.Message = StatusWarn, fmt.Sprintf("%d environment variable(s) override config values", len(c.Details))
+	} else {
+		c.Status, c.Message = StatusPass, "no environment variables override config values"
+	}
+	return c
+}
@@ -0,0 +1,152 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+	"github.com/jsburckhardt/co-config/internal/copilot"
+	"github.com/jsburckhardt/co-config/internal/schemacache"
+)
+
+// findCheck returns the check with the given name.
+func findCheck(t *testing.T, checks []Check, name string) Check {
+	t.Helper()
+	for _, c := range checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("check %q not found", name)
+	return Check{}
+}
+
+func writeFile(t *testing.T, path, content string, perm os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// UT-DOC-001: a healthy setup passes every check
+func TestRunHealthy(t *testing.T) {
+	dir := t.TempDir()
+	paths := config.ScopePaths(dir)
+	paths[config.ScopeUser] = filepath.Join(dir, "user", "config.json")
+	writeFile(t, paths[config.ScopeUser], `{"model": "gpt-4.1", "trusted_folders": ["`+dir+`"]}`, 0600)
+
+	detection := schemacache.Result{
+		Version: "0.0.412",
+		Schema:  []copilot.SchemaField{{Name: "model", Type: "enum", Options: []string{"gpt-4.1"}}},
+		EnvVars: []copilot.EnvVarInfo{{Names: []string{"COPILOT_MODEL"}}},
+	}
+	checks := Run(Input{Detection: detection, Paths: paths, LookupEnv: func(string) (string, bool) { return "", false }})
+	for _, c := range checks {
+		if c.Status != StatusPass {
+			t.Errorf("%s: %s %s %v", c.Name, c.Status, c.Message, c.Details)
+		}
+	}
+	if Failed(checks) != 0 {
+		t.Errorf("Failed = %d, want 0", Failed(checks))
+	}
+}
+
+// UT-DOC-002: broken installs and invalid files fail
+func TestRunFailures(t *testing.T) {
+	dir := t.TempDir()
+	paths := config.ScopePaths(dir)
+	paths[config.ScopeUser] = filepath.Join(dir, "user", "config.json")
+	writeFile(t, paths[config.ScopeUser], `{"store_token_plaintext": true, "copilot_tokens": {"u": "gho_x"}}`, 0600)
+	writeFile(t, paths[config.ScopeProject], `{not json`, 0600)
+
+	detection := schemacache.Result{VersionErr: copilot.ErrCopilotNotInstalled, SchemaErr: copilot.ErrCopilotNotInstalled}
+	checks := Run(Input{Detection: detection, Paths: paths})
+
+	for _, name := range []string{"copilot binary", "config schema", "project config", "plaintext tokens"} {
+		if c := findCheck(t, checks, name); c.Status != StatusFail {
+			t.Errorf("%s = %s (%s), want fail", name, c.Status, c.Message)
+		}
+	}
+	if Failed(checks) != 4 {
+		t.Errorf("Failed = %d, want 4", Failed(checks))
+	}
+}
+
+// UT-DOC-003: permissions, missing folders, URL conflicts and env overrides warn
+func TestRunWarnings(t *testing.T) {
+	dir := t.TempDir()
+	paths := config.ScopePaths(dir)
+	paths[config.ScopeUser] = filepath.Join(dir, "user", "config.json")
+	writeFile(t, paths[config.ScopeUser], `{
+		"model": "gpt-4.1",
+		"trusted_folders": ["`+filepath.Join(dir, "gone")+`"],
+		"allowed_urls": ["api.github.com"],
+		"denied_urls": ["*.github.com"]
+	}`, 0644)
+
+	detection := schemacache.Result{
+		Version: "0.0.412",
+		Schema:  []copilot.SchemaField{{Name: "model", Type: "string"}},
+		EnvVars: []copilot.EnvVarInfo{{Names: []string{"COPILOT_MODEL"}}},
+	}
+	env := map[string]string{"COPILOT_MODEL": "gpt-5"}
+	lookup := func(name string) (string, bool) { v, ok := env[name]; return v, ok }
+	checks := Run(Input{Detection: detection, Paths: paths, LookupEnv: lookup})
+
+	names := []string{"trusted folders", "URL rules", "environment overrides"}
+	if runtime.GOOS != "windows" {
+		names = append(names, "user permissions")
+	}
+	for _, name := range names {
+		if c := findCheck(t, checks, name); c.Status != StatusWarn {
+			t.Errorf("%s = %s (%s), want warn", name, c.Status, c.Message)
+		}
+	}
+	overrides := findCheck(t, checks, "environment overrides")
+	if len(overrides.Details) != 1 || !strings.Contains(overrides.Details[0], "COPILOT_MODEL=gpt-5 overrides model = gpt-4.1 (user scope)") {
+		t.Errorf("env override details = %v", overrides.Details)
+	}
+	if Failed(checks) != 0 {
+		t.Errorf("Failed = %d, want 0", Failed(checks))
+	}
+}
+
+// UT-DOC-004: tokens nested in objects and lists are found and never printed
+func TestNestedTokens(t *testing.T) {
+	dir := t.TempDir()
+	paths := config.ScopePaths(dir)
+	paths[config.ScopeUser] = filepath.Join(dir, "user", "config.json")
+	writeFile(t, paths[config.ScopeUser], `{
+		"store_token_plaintext": true,
+		"mcp": {"env": {"TOKEN": "ghp_nestedsecret"}},
+		"logged_in_users": [{"login": "octocat"}]
+	}`, 0600)
+
+	detection := schemacache.Result{
+		Version: "0.0.412",
+		Schema:  []copilot.SchemaField{{Name: "mcp", Type: "json"}},
+		EnvVars: []copilot.EnvVarInfo{{Names: []string{"COPILOT_MCP"}}},
+	}
+	lookup := func(name string) (string, bool) { return `{}`, name == "COPILOT_MCP" }
+	checks := Run(Input{Detection: detection, Paths: paths, LookupEnv: lookup})
+
+	tokens := findCheck(t, checks, "plaintext tokens")
+	if tokens.Status != StatusFail || len(tokens.Details) != 1 || !strings.HasSuffix(tokens.Details[0], ": mcp") {
+		t.Errorf("plaintext tokens = %s %v, want a failure naming only mcp", tokens.Status, tokens.Details)
+	}
+	overrides := findCheck(t, checks, "environment overrides")
+	if len(overrides.Details) != 1 || !strings.Contains(overrides.Details[0], "overrides mcp = [redacted") {
+		t.Errorf("env override details = %v", overrides.Details)
+	}
+	for _, c := range checks {
+		if strings.Contains(c.Message+strings.Join(c.Details, " "), "ghp_nestedsecret") {
+			t.Errorf("%s leaks the token: %v", c.Name, c.Details)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package profile stores named snapshots of non-sensitive settings that can
+// be applied to any config scope in one step. Profiles are saved as settings
+// bundles (see package bundle) in ccc's own data directory.
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jsburckhardt/co-config/internal/bundle"
+	"github.com/jsburckhardt/co-config/internal/config"
+)
+
+var (
+	ErrProfileNotFound = errors.New("profile not found")
+	ErrInvalidName     = errors.New("invalid profile name")
+	ErrEmptyProfile    = errors.New("profile has no settings")
+)
+
+// validName allows names that are safe as file names on every platform.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// DefaultDir returns the profile directory next to the user config file,
+// e.g. ~/.copilot/ccc/profiles.
+func DefaultDir() string {
+	return filepath.Join(filepath.Dir(config.DefaultPath()), "ccc", "profiles")
+}
+
+// Profile is a named set of settings.
+type Profile struct {
+	Name   string
+	Bundle *bundle.Bundle
+}
+
+// Snapshot captures the selected non-sensitive keys of cfg as a profile.
+// Sensitive keys are never captured; they are returned so callers can report them.
+func Snapshot(name string, cfg *config.Config, filter bundle.Filter) (*Profile, []string, error) {
+	if err := checkName(name); err != nil {
+		return nil, nil, err
+	}
+	b, stripped := bundle.Export(cfg, filter)
+	if len(b.Settings) == 0 {
+		return nil, stripped, ErrEmptyProfile
+	}
+	return &Profile{Name: name, Bundle: b}, stripped, nil
+}
+
+// Keys returns the profile's keys, sorted.
+func (p *Profile) Keys() []string {
+	keys := make([]string, 0, len(p.Bundle.Settings))
+	for k := range p.Bundle.Settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Apply sets the profile's keys in cfg, keeping all other keys, and returns
+// the changes made.
+func (p *Profile) Apply(cfg *config.Config) []config.Change {
+	return p.Bundle.Apply(cfg, bundle.ModeMerge)
+}
+
+// Diff returns the changes Apply would make to cfg without modifying it.
+func (p *Profile) Diff(cfg *config.Config) []config.Change {
+	return p.Apply(cfg.Clone())
+}
+
+// Store reads and writes profiles in a directory.
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store rooted at dir. The directory is created on first save.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// Save writes the profile, replacing any profile with the same name.
+func (s *Store) Save(p *Profile) error {
+	if err := checkName(p.Name); err != nil {
+		return err
+	}
+	data, err := p.Bundle.Marshal(bundle.FormatJSON)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("creating profile directory: %w", err)
+	}
+	if err := os.WriteFile(s.path(p.Name), data, 0600); err != nil {
+		return fmt.Errorf("writing profile: %w", err)
+	}
+	return nil
+}
+
+// Load reads the named profile.
+func (s *Store) Load(name string) (*Profile, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(s.path(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+		}
+		return nil, fmt.Errorf("reading profile: %w", err)
+	}
+	b, _, err := bundle.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	return &Profile{Name: name, Bundle: b}, nil
+}
+
+// List returns every stored profile sorted by name. A missing directory
+// means there are no profiles; unreadable profiles are skipped.
+func (s *Store) List() ([]*Profile, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing profiles: %w", err)
+	}
+	var profiles []*Profile
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() || checkName(name) != nil {
+			continue
+		}
+		p, err := s.Load(name)
+		if err != nil {
+			continue
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, nil
+}
+
+// Delete removes the named profile.
+func (s *Store) Delete(name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+		}
+		return fmt.Errorf("deleting profile: %w", err)
+	}
+	return nil
+}
+
+func checkName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("%w %q: use letters, digits, '.', '_' or '-'", ErrInvalidName, name)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package profile
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jsburckhardt/co-config/internal/bundle"
+	"github.com/jsburckhardt/co-config/internal/config"
+)
+
+// UT-PRF-001: Snapshot captures selected non-sensitive keys
+func TestSnapshot(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Set("model", "claude-opus-4.5")
+	cfg.Set("reasoning_effort", "high")
+	cfg.Set("theme", "dark")
+	cfg.Set("copilot_tokens", map[string]any{"k": "v"})
+
+	p, stripped, err := Snapshot("deep-work", cfg, bundle.Filter{Categories: []string{"Model & AI"}})
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if !reflect.DeepEqual(p.Keys(), []string{"model", "reasoning_effort"}) {
+		t.Errorf("keys = %v", p.Keys())
+	}
+	if len(stripped) != 0 {
+		t.Errorf("stripped = %v, want none (category filter excludes tokens)", stripped)
+	}
+
+	p, stripped, _ = Snapshot("all", cfg, bundle.Filter{})
+	if _, ok := p.Bundle.Settings["copilot_tokens"]; ok || len(stripped) != 1 {
+		t.Error("Snapshot must never capture sensitive keys")
+	}
+
+	if _, _, err := Snapshot("../escape", cfg, bundle.Filter{}); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("invalid name error = %v", err)
+	}
+	if _, _, err := Snapshot("empty", cfg, bundle.Filter{Keys: []string{"missing"}}); !errors.Is(err, ErrEmptyProfile) {
+		t.Errorf("empty profile error = %v", err)
+	}
+}
+
+// UT-PRF-002: Store saves, lists, loads and deletes profiles
+func TestStore(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if profiles, err := store.List(); err != nil || len(profiles) != 0 {
+		t.Fatalf("List on empty store = %v, %v", profiles, err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.Set("streamer_mode", true)
+	cfg.Set("banner", "never")
+	for _, name := range []string{"screen-share", "deep-work"} {
+		p, _, err := Snapshot(name, cfg, bundle.Filter{})
+		if err != nil {
+			t.Fatalf("Snapshot failed: %v", err)
+		}
+		if err := store.Save(p); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	profiles, err := store.List()
+	if err != nil || len(profiles) != 2 || profiles[0].Name != "deep-work" {
+		t.Fatalf("List = %v, %v", profiles, err)
+	}
+	loaded, err := store.Load("screen-share")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Bundle.Settings, cfg.Data()) {
+		t.Errorf("loaded settings = %v", loaded.Bundle.Settings)
+	}
+
+	if err := store.Delete("screen-share"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Load("screen-share"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("Load after delete error = %v", err)
+	}
+	if err := store.Delete("screen-share"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("Delete missing error = %v", err)
+	}
+}
+
+// UT-PRF-003: Diff previews Apply without modifying the config
+func TestDiffAndApply(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Set("model", "fast")
+	cfg.Set("theme", "dark")
+
+	p := &Profile{Name: "deep", Bundle: &bundle.Bundle{Version: bundle.FormatVersion, Settings: map[string]any{"model": "opus", "theme": "dark", "experimental": true}}}
+	changes := p.Diff(cfg)
+	if len(changes) != 2 || changes[0].Key != "experimental" || changes[1].Key != "model" {
+		t.Fatalf("Diff = %+v", changes)
+	}
+	if cfg.Get("model") != "fast" {
+		t.Error("Diff must not modify the config")
+	}
+
+	p.Apply(cfg)
+	if cfg.Get("model") != "opus" || cfg.Get("experimental") != true || cfg.Get("theme") != "dark" {
+		t.Errorf("Apply result = %v", cfg.Data())
+	}
+}
+
+// UT-PRF-004: Snapshot leaves out entries holding nested tokens
+func TestSnapshotNestedTokens(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Set("theme", "dark")
+	cfg.Set("mcp", map[string]any{"env": map[string]any{"TOKEN": "ghp_nested"}})
+
+	p, stripped, err := Snapshot("work", cfg, bundle.Filter{})
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if !reflect.DeepEqual(p.Keys(), []string{"theme"}) || !reflect.DeepEqual(stripped, []string{"mcp"}) {
+		t.Errorf("keys = %v, stripped = %v", p.Keys(), stripped)
+	}
+}
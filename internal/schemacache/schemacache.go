@@ -0,0 +1,280 @@
+// Package schemacache keeps the parsed Copilot CLI schema and environment
+// variable list on disk, keyed by the Copilot CLI version, so startup only
+// has to run `copilot version` while the installed version is unchanged.
+package schemacache
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+	"github.com/jsburckhardt/co-config/internal/copilot"
+)
+
+// FormatVersion is bumped whenever the parsers change what they produce, so
+// entries written by an older ccc are detected again instead of reused.
+const FormatVersion = 1
+
+var (
+	ErrNotCached      = errors.New("schema not cached")
+	ErrInvalidVersion = errors.New("invalid copilot version")
+)
+
+// validVersion allows versions that are safe as file names.
+var validVersion = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z.+-]*$`)
+
+// DefaultDir returns the cache directory next to the user config file,
+// e.g. ~/.copilot/ccc/cache.
+func DefaultDir() string {
+	return filepath.Join(filepath.Dir(config.DefaultPath()), "ccc", "cache")
+}
+
+// Entry is the detection result cached for one Copilot CLI version.
+type Entry struct {
+	Format     int                   `json:"format"`
+	Version    string                `json:"version"`
+	DetectedAt time.Time             `json:"detected_at"`
+	Schema     []copilot.SchemaField `json:"schema"`
+	EnvVars    []copilot.EnvVarInfo  `json:"env_vars"`
+	// DiffAcknowledged is set once the changes since the previous version were shown.
+	DiffAcknowledged bool `json:"diff_acknowledged,omitempty"`
+}
+
+// Store reads and writes cache entries in a directory.
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store rooted at dir. The directory is created on first save.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) path(version string) string {
+	return filepath.Join(s.dir, version+".json")
+}
+
+// Load reads the entry for version. Missing entries and entries written in
+// another format return ErrNotCached.
+func (s *Store) Load(version string) (*Entry, error) {
+	if !validVersion.MatchString(version) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidVersion, version)
+	}
+	data, err := os.ReadFile(s.path(version))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotCached, version)
+		}
+		return nil, fmt.Errorf("reading schema cache: %w", err)
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Format != FormatVersion || e.Version != version {
+		return nil, fmt.Errorf("%w: %s", ErrNotCached, version)
+	}
+	return &e, nil
+}
+
+// Save writes the entry, replacing any entry for the same version.
+func (s *Store) Save(e *Entry) error {
+	if !validVersion.MatchString(e.Version) {
+		return fmt.Errorf("%w: %q", ErrInvalidVersion, e.Version)
+	}
+	e.Format = FormatVersion
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding schema cache: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("creating schema cache directory: %w", err)
+	}
+	// Write atomically so concurrent ccc processes never read a partial entry.
+	if err := config.WriteFileAtomic(s.path(e.Version), data, 0600); err != nil {
+		return fmt.Errorf("writing schema cache: %w", err)
+	}
+	return nil
+}
+
+// Acknowledge records that the schema changes leading to version were shown,
+// so later runs with the same version do not show them again.
+func (s *Store) Acknowledge(version string) error {
+	e, err := s.Load(version)
+	if err != nil {
+		return err
+	}
+	if e.DiffAcknowledged {
+		return nil
+	}
+	e.DiffAcknowledged = true
+	return s.Save(e)
+}
+
+// Versions returns the versions with a cached entry, oldest first.
+func (s *Store) Versions() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing schema cache: %w", err)
+	}
+	var versions []string
+	for _, de := range entries {
+		version, ok := strings.CutSuffix(de.Name(), ".json")
+		if !ok || de.IsDir() || !validVersion.MatchString(version) {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return CompareVersions(versions[i], versions[j]) < 0 })
+	return versions, nil
+}
+
+// Previous returns the entry for the newest cached version older than
+// version, or ErrNotCached if there is none.
+func (s *Store) Previous(version string) (*Entry, error) {
+	versions, err := s.Versions()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		if CompareVersions(versions[i], version) >= 0 {
+			continue
+		}
+		if e, err := s.Load(versions[i]); err == nil {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no version before %s", ErrNotCached, version)
+}
+
+// CompareVersions compares dotted versions numerically part by part,
+// falling back to string comparison for non-numeric parts. It returns -1, 0 or 1.
+func CompareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var ap, bp string
+		if i < len(as) {
+			ap = as[i]
+		}
+		if i < len(bs) {
+			bp = bs[i]
+		}
+		an, aerr := strconv.Atoi(ap)
+		bn, berr := strconv.Atoi(bp)
+		switch {
+		case aerr == nil && berr == nil && an != bn:
+			return cmp.Compare(an, bn)
+		case (aerr != nil || berr != nil) && ap != bp:
+			return strings.Compare(ap, bp)
+		}
+	}
+	return 0
+}
+
+// Clear removes every cached entry and returns how many were removed.
+// A missing directory means there is nothing to clear.
+func (s *Store) Clear() (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("listing schema cache: %w", err)
+	}
+	removed := 0
+	for _, de := range entries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), ".json") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, de.Name())); err != nil {
+			return removed, fmt.Errorf("clearing schema cache: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Result is what Detect found. Each part carries its own error so callers
+// can fall back per part.
+type Result struct {
+	Version    string
+	Schema     []copilot.SchemaField
+	EnvVars    []copilot.EnvVarInfo
+	VersionErr error
+	SchemaErr  error
+	EnvVarsErr error
+	Cached     bool // schema and env vars came from the cache
+}
+
+// Detect returns the schema and environment variables for the installed
+// Copilot CLI. It runs `copilot version` first and reuses the cached entry
+// for that version; on a cache miss the schema and environment detections
+// run concurrently and the result is cached. With refresh set the cache is
+// not read and all three detections run concurrently.
+func Detect(ctx context.Context, r copilot.Runner, s *Store, refresh bool) Result {
+	var res Result
+	if refresh {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res.Version, res.VersionErr = copilot.DetectVersionWith(ctx, r)
+		}()
+		detectSchemaAndEnv(ctx, r, &res)
+		wg.Wait()
+	} else {
+		res.Version, res.VersionErr = copilot.DetectVersionWith(ctx, r)
+		if errors.Is(res.VersionErr, copilot.ErrCopilotNotInstalled) {
+			res.SchemaErr, res.EnvVarsErr = res.VersionErr, res.VersionErr
+			return res
+		}
+		if res.VersionErr == nil {
+			e, err := s.Load(res.Version)
+			if err == nil {
+				slog.Info("using cached copilot schema", "version", res.Version)
+				res.Schema, res.EnvVars, res.Cached = e.Schema, e.EnvVars, true
+				return res
+			}
+			if !errors.Is(err, ErrNotCached) {
+				slog.Warn("failed to read schema cache", "error", err)
+			}
+		}
+		detectSchemaAndEnv(ctx, r, &res)
+	}
+
+	if res.VersionErr == nil && res.SchemaErr == nil && res.EnvVarsErr == nil {
+		e := &Entry{Version: res.Version, DetectedAt: time.Now().UTC(), Schema: res.Schema, EnvVars: res.EnvVars}
+		// A refresh keeps the acknowledgement, so the changes are not shown twice.
+		if old, err := s.Load(res.Version); err == nil {
+			e.DiffAcknowledged = old.DiffAcknowledged
+		}
+		if err := s.Save(e); err != nil {
+			slog.Warn("failed to write schema cache", "error", err)
+		}
+	}
+	return res
+}
+
+// detectSchemaAndEnv runs the schema and environment detections concurrently.
+func detectSchemaAndEnv(ctx context.Context, r copilot.Runner, res *Result) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		res.EnvVars, res.EnvVarsErr = copilot.DetectEnvVarsWith(ctx, r)
+	}()
+	res.Schema, res.SchemaErr = copilot.DetectSchemaWith(ctx, r)
+	wg.Wait()
+}
@@ -0,0 +1,171 @@
+package schemacache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jsburckhardt/co-config/internal/copilot"
+	"github.com/jsburckhardt/co-config/internal/copilottest"
+)
+
+// UT-SCC-001: Save, Load and Clear round-trip entries keyed by version
+func TestStoreRoundTrip(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "cache"))
+
+	if _, err := store.Load("1.0.0"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("Load(missing) error = %v, want ErrNotCached", err)
+	}
+	if n, err := store.Clear(); err != nil || n != 0 {
+		t.Errorf("Clear(missing dir) = %d, %v", n, err)
+	}
+
+	entry := &Entry{
+		Version: "1.0.0",
+		Schema:  []copilot.SchemaField{{Name: "model", Type: "enum", Options: []string{"a", "b"}}},
+		EnvVars: []copilot.EnvVarInfo{{Names: []string{"COPILOT_MODEL"}}},
+	}
+	if err := store.Save(entry); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	got, err := store.Load("1.0.0")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(got.Schema, entry.Schema) || !reflect.DeepEqual(got.EnvVars, entry.EnvVars) {
+		t.Errorf("Load = %+v, want %+v", got, entry)
+	}
+	if _, err := store.Load("2.0.0"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("Load(other version) error = %v, want ErrNotCached", err)
+	}
+	if _, err := store.Load("../escape"); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Load(../escape) error = %v, want ErrInvalidVersion", err)
+	}
+
+	// Entries written by another cache format are ignored.
+	if err := os.WriteFile(store.path("1.0.0"), []byte(`{"format": 0, "version": "1.0.0"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load("1.0.0"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("Load(old format) error = %v, want ErrNotCached", err)
+	}
+
+	if n, err := store.Clear(); err != nil || n != 1 {
+		t.Errorf("Clear = %d, %v; want 1", n, err)
+	}
+}
+
+// UT-SCC-002: Detect caches on a miss, reuses the entry on a hit and re-detects on refresh
+func TestDetectUsesCache(t *testing.T) {
+	store := NewStore(t.TempDir())
+	r := copilottest.NewTestdataRunner(t)
+	ctx := context.Background()
+	all := []string{"help config", "help environment", "version"}
+
+	res := Detect(ctx, r, store, false)
+	if res.Cached || res.Version != "0.0.412" || len(res.Schema) == 0 || len(res.EnvVars) == 0 {
+		t.Fatalf("first Detect = %+v", res)
+	}
+	if calls := r.TakeCalls(); !reflect.DeepEqual(calls, all) {
+		t.Errorf("miss calls = %v, want %v", calls, all)
+	}
+
+	hit := Detect(ctx, r, store, false)
+	if !hit.Cached || !reflect.DeepEqual(hit.Schema, res.Schema) || !reflect.DeepEqual(hit.EnvVars, res.EnvVars) {
+		t.Errorf("second Detect should come from the cache unchanged")
+	}
+	if calls := r.TakeCalls(); !reflect.DeepEqual(calls, []string{"version"}) {
+		t.Errorf("hit calls = %v, want only version", calls)
+	}
+
+	if refreshed := Detect(ctx, r, store, true); refreshed.Cached {
+		t.Error("Detect with refresh must not use the cache")
+	}
+	if calls := r.TakeCalls(); !reflect.DeepEqual(calls, all) {
+		t.Errorf("refresh calls = %v, want %v", calls, all)
+	}
+}
+
+// UT-SCC-003: Detect reports a missing binary for every part and caches nothing
+func TestDetectNotInstalled(t *testing.T) {
+	dir := t.TempDir()
+	r := &copilottest.Runner{Err: copilot.ErrCopilotNotInstalled}
+
+	res := Detect(context.Background(), r, NewStore(dir), false)
+	for _, err := range []error{res.VersionErr, res.SchemaErr, res.EnvVarsErr} {
+		if !errors.Is(err, copilot.ErrCopilotNotInstalled) {
+			t.Errorf("error = %v, want ErrCopilotNotInstalled", err)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("cache dir has %d entries, want none", len(entries))
+	}
+}
+
+// UT-SCC-004: Versions sorts numerically and Previous finds the newest older entry
+func TestPreviousVersion(t *testing.T) {
+	store := NewStore(t.TempDir())
+	for _, v := range []string{"0.0.9", "0.0.412", "0.0.10"} {
+		if err := store.Save(&Entry{Version: v}); err != nil {
+			t.Fatalf("Save(%s) failed: %v", v, err)
+		}
+	}
+
+	versions, err := store.Versions()
+	if err != nil {
+		t.Fatalf("Versions failed: %v", err)
+	}
+	if want := []string{"0.0.9", "0.0.10", "0.0.412"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("Versions = %v, want %v", versions, want)
+	}
+
+	prev, err := store.Previous("0.0.412")
+	if err != nil || prev.Version != "0.0.10" {
+		t.Errorf("Previous(0.0.412) = %v, %v; want 0.0.10", prev, err)
+	}
+	if _, err := store.Previous("0.0.9"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("Previous(oldest) error = %v, want ErrNotCached", err)
+	}
+	if CompareVersions("1.2.0", "1.10.0") >= 0 || CompareVersions("1.0.0", "1.0.0") != 0 {
+		t.Error("CompareVersions should compare parts numerically")
+	}
+}
+
+// UT-SCC-005: Acknowledge is recorded per version and survives a cache hit and a refresh
+func TestAcknowledge(t *testing.T) {
+	store := NewStore(t.TempDir())
+	r := copilottest.NewTestdataRunner(t)
+	ctx := context.Background()
+
+	if err := store.Acknowledge("0.0.412"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("Acknowledge(uncached) error = %v, want ErrNotCached", err)
+	}
+	// A non-TUI command caches the version first; the changes are still pending.
+	Detect(ctx, r, store, false)
+	if e, err := store.Load("0.0.412"); err != nil || e.DiffAcknowledged {
+		t.Fatalf("Load after Detect = %+v, %v; want unacknowledged", e, err)
+	}
+	if res := Detect(ctx, r, store, false); !res.Cached {
+		t.Fatal("second Detect should hit the cache")
+	}
+	if e, _ := store.Load("0.0.412"); e.DiffAcknowledged {
+		t.Error("a cache hit must not acknowledge the changes")
+	}
+
+	if err := store.Acknowledge("0.0.412"); err != nil {
+		t.Fatalf("Acknowledge: %v", err)
+	}
+	Detect(ctx, r, store, true)
+	if e, err := store.Load("0.0.412"); err != nil || !e.DiffAcknowledged {
+		t.Errorf("Load after refresh = %+v, %v; want acknowledged", e, err)
+	}
+	if err := store.Save(&Entry{Version: "0.0.500"}); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := store.Load("0.0.500"); e.DiffAcknowledged {
+		t.Error("acknowledging one version must not acknowledge another")
+	}
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+)
+
+// BackupPanel lists the automatic backups of the active scope's config file
+// so one can be restored.
+type BackupPanel struct {
+	path    string
+	backups []config.Backup
+	cursor  int
+	offset  int
+	width   int
+	height  int
+}
+
+// NewBackupPanel creates a backup panel for the config file at path.
+func NewBackupPanel(path string, backups []config.Backup) *BackupPanel {
+	return &BackupPanel{path: path, backups: backups}
+}
+
+// SetSize updates the panel content dimensions.
+func (p *BackupPanel) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+	p.ensureVisible()
+}
+
+// Up moves cursor up one entry.
+func (p *BackupPanel) Up() {
+	if p.cursor > 0 {
+		p.cursor--
+		p.ensureVisible()
+	}
+}
+
+// Down moves cursor down one entry.
+func (p *BackupPanel) Down() {
+	if p.cursor < len(p.backups)-1 {
+		p.cursor++
+		p.ensureVisible()
+	}
+}
+
+// Selected returns the highlighted backup, or nil if there are none.
+func (p *BackupPanel) Selected() *config.Backup {
+	if p.cursor >= 0 && p.cursor < len(p.backups) {
+		b := p.backups[p.cursor]
+		return &b
+	}
+	return nil
+}
+
+// visibleRows returns how many backups fit below the title lines.
+func (p *BackupPanel) visibleRows() int {
+	visible := p.height - 3
+	if visible < 1 {
+		visible = 1
+	}
+	return visible
+}
+
+// ensureVisible adjusts offset so the cursor is within the visible viewport.
+func (p *BackupPanel) ensureVisible() {
+	if p.height <= 0 || len(p.backups) == 0 {
+		return
+	}
+	visible := p.visibleRows()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+visible {
+		p.offset = p.cursor - visible + 1
+	}
+}
+
+// View renders the panel content.
+func (p *BackupPanel) View() string {
+	if p.width <= 0 || p.height <= 0 {
+		return ""
+	}
+
+	lines := []string{
+		detailHeaderStyle.Render("Restore backup"),
+		detailNoteStyle.Render(p.path),
+		"",
+	}
+
+	if len(p.backups) == 0 {
+		lines = append(lines, envVarDescStyle.Render("No backups yet — one is kept each time the file is saved"))
+	}
+
+	end := p.offset + p.visibleRows()
+	if end > len(p.backups) {
+		end = len(p.backups)
+	}
+	for i := p.offset; i < end; i++ {
+		b := p.backups[i]
+		line := fmt.Sprintf("%-24s %s  %6d bytes", b.ID, b.Time.Local().Format(time.DateTime), b.Size)
+		if i == p.cursor {
+			lines = append(lines, selectedItemStyle.Render("▶ "+line))
+		} else {
+			lines = append(lines, itemStyle.Render("  "+line))
+		}
+	}
+
+	for len(lines) < p.height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,240 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+	"github.com/jsburckhardt/co-config/internal/sensitive"
+)
+
+// compareRow is one key of the scope comparison.
+type compareRow struct {
+	key      string
+	left     any
+	right    any
+	leftSet  bool
+	rightSet bool
+}
+
+// differs reports whether the two scopes disagree on the key.
+func (r compareRow) differs() bool {
+	return r.leftSet != r.rightSet || !reflect.DeepEqual(r.left, r.right)
+}
+
+// ComparePanel shows the keys of two scopes side by side, highlighting the
+// keys whose values differ.
+type ComparePanel struct {
+	left    config.Scope
+	right   config.Scope
+	paths   map[config.Scope]string
+	rows    []compareRow
+	message string
+	cursor  int
+	offset  int
+	width   int
+	height  int
+}
+
+// NewComparePanel creates a comparison of the left and right scopes.
+func NewComparePanel(left, right config.Scope, paths map[config.Scope]string) *ComparePanel {
+	return &ComparePanel{left: left, right: right, paths: paths}
+}
+
+// Scopes returns the left and right scopes.
+func (p *ComparePanel) Scopes() (config.Scope, config.Scope) {
+	return p.left, p.right
+}
+
+// CycleLeft moves the left column to the next scope not shown on the right.
+func (p *ComparePanel) CycleLeft() {
+	p.left = nextScope(p.left)
+	if p.left == p.right {
+		p.left = nextScope(p.left)
+	}
+}
+
+// CycleRight moves the right column to the next scope not shown on the left.
+func (p *ComparePanel) CycleRight() {
+	p.right = nextScope(p.right)
+	if p.right == p.left {
+		p.right = nextScope(p.right)
+	}
+}
+
+// SetLayers recomputes the rows from the scopes' configs, keeping the cursor on the same key.
+func (p *ComparePanel) SetLayers(layers map[config.Scope]*config.Config) {
+	var selected string
+	if row, ok := p.Selected(); ok {
+		selected = row.key
+	}
+	left, right := layers[p.left].Data(), layers[p.right].Data()
+	keys := make(map[string]bool, len(left)+len(right))
+	for k := range left {
+		keys[k] = true
+	}
+	for k := range right {
+		keys[k] = true
+	}
+	p.rows = p.rows[:0]
+	for k := range keys {
+		lv, lok := left[k]
+		rv, rok := right[k]
+		p.rows = append(p.rows, compareRow{key: k, left: lv, right: rv, leftSet: lok, rightSet: rok})
+	}
+	sort.Slice(p.rows, func(i, j int) bool { return p.rows[i].key < p.rows[j].key })
+
+	p.cursor = 0
+	for i, r := range p.rows {
+		if r.key == selected {
+			p.cursor = i
+		}
+	}
+	p.ensureVisible()
+}
+
+// Selected returns the row under the cursor.
+func (p *ComparePanel) Selected() (compareRow, bool) {
+	if p.cursor < 0 || p.cursor >= len(p.rows) {
+		return compareRow{}, false
+	}
+	return p.rows[p.cursor], true
+}
+
+// Select moves the cursor to key, if either scope sets it.
+func (p *ComparePanel) Select(key string) {
+	for i, r := range p.rows {
+		if r.key == key {
+			p.cursor = i
+			p.ensureVisible()
+			return
+		}
+	}
+}
+
+// SetMessage shows a one-line status below the table.
+func (p *ComparePanel) SetMessage(msg string) {
+	p.message = msg
+}
+
+// SetSize updates the panel content dimensions.
+func (p *ComparePanel) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+	p.ensureVisible()
+}
+
+// Up moves the cursor up one row.
+func (p *ComparePanel) Up() {
+	if p.cursor > 0 {
+		p.cursor--
+		p.ensureVisible()
+	}
+}
+
+// Down moves the cursor down one row.
+func (p *ComparePanel) Down() {
+	if p.cursor < len(p.rows)-1 {
+		p.cursor++
+		p.ensureVisible()
+	}
+}
+
+// visibleRows returns how many rows fit below the title, paths, column
+// headers and status line.
+func (p *ComparePanel) visibleRows() int {
+	return max(p.height-6, 1)
+}
+
+func (p *ComparePanel) ensureVisible() {
+	visible := p.visibleRows()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+visible {
+		p.offset = p.cursor - visible + 1
+	}
+}
+
+// View renders the comparison table.
+func (p *ComparePanel) View() string {
+	if p.width <= 0 || p.height <= 0 {
+		return ""
+	}
+
+	differing := 0
+	for _, r := range p.rows {
+		if r.differs() {
+			differing++
+		}
+	}
+	lines := []string{
+		detailHeaderStyle.Render(fmt.Sprintf("Compare %s ↔ %s", p.left.Label(), p.right.Label())) +
+			detailNoteStyle.Render(fmt.Sprintf("  %d of %d keys differ", differing, len(p.rows))),
+		detailNoteStyle.Render(truncate(p.paths[p.left]+" ↔ "+p.paths[p.right], p.width)),
+		"",
+	}
+
+	keyWidth := min(max(p.width/3, 12), 32)
+	valWidth := max((p.width-keyWidth-6)/2, 6)
+	header := fmt.Sprintf("  %-*s %-*s %s", keyWidth, "KEY", valWidth, strings.ToUpper(p.left.Label()), strings.ToUpper(p.right.Label()))
+	lines = append(lines, groupHeaderStyle.Render(truncate(header, p.width)))
+
+	if len(p.rows) == 0 {
+		lines = append(lines, envVarDescStyle.Render("No keys are set in either scope"))
+	}
+	end := min(p.offset+p.visibleRows(), len(p.rows))
+	for i := p.offset; i < end; i++ {
+		lines = append(lines, p.renderRow(p.rows[i], i == p.cursor, keyWidth, valWidth))
+	}
+
+	for len(lines) < p.height-1 {
+		lines = append(lines, "")
+	}
+	if p.message != "" {
+		lines = append(lines, detailNoteStyle.Render(truncate(p.message, p.width)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (p *ComparePanel) renderRow(r compareRow, selected bool, keyWidth, valWidth int) string {
+	prefix := "  "
+	if selected {
+		prefix = "▶ "
+	}
+	key := fmt.Sprintf("%-*s", keyWidth, truncate(r.key, keyWidth))
+	left := fmt.Sprintf("%-*s", valWidth, compareValue(r.key, r.left, r.leftSet, valWidth))
+	right := compareValue(r.key, r.right, r.rightSet, valWidth)
+
+	style := envVarValueSetStyle
+	if r.differs() {
+		style = diffValueStyle
+	}
+	keyStyle := itemStyle
+	if selected {
+		keyStyle = selectedItemStyle
+	}
+	return keyStyle.Render(prefix+key) + " " + style.Render(left) + " " + style.Render(right)
+}
+
+// compareValue formats one side of a row, masking sensitive values.
+func compareValue(key string, value any, set bool, width int) string {
+	switch {
+	case !set:
+		return "—"
+	case isSensitiveValue(key, value):
+		return truncate("🔒 "+sensitive.MaskValue(value), width)
+	}
+	return formatValueCompact(value, "", width)
+}
+
+// truncate shortens s to width runes, ending with "…" when cut.
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if width <= 0 || len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+)
+
+// ConflictPanel explains that the config file changed on disk since it was
+// loaded and offers to merge, overwrite, or cancel the save.
+type ConflictPanel struct {
+	path      string
+	ours      []string
+	theirs    []string
+	conflicts []config.Conflict
+	loadErr   error
+	width     int
+	height    int
+}
+
+// NewConflictPanel creates a conflict panel. ours lists keys edited in memory,
+// theirs lists keys changed on disk, and loadErr is set when the on-disk file
+// could not be parsed (only overwriting is possible then).
+func NewConflictPanel(path string, ours, theirs []string, conflicts []config.Conflict, loadErr error) *ConflictPanel {
+	return &ConflictPanel{path: path, ours: ours, theirs: theirs, conflicts: conflicts, loadErr: loadErr}
+}
+
+// SetSize updates the panel content dimensions.
+func (p *ConflictPanel) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+}
+
+// CanMerge reports whether the on-disk version could be loaded for merging.
+func (p *ConflictPanel) CanMerge() bool {
+	return p.loadErr == nil
+}
+
+// View renders the panel content.
+func (p *ConflictPanel) View() string {
+	if p.width <= 0 || p.height <= 0 {
+		return ""
+	}
+
+	lines := []string{
+		errorStyle.Render("⚠ The config file changed on disk since it was loaded"),
+		detailNoteStyle.Render(p.path),
+		"",
+	}
+
+	if p.loadErr != nil {
+		lines = append(lines,
+			detailDescStyle.Render("The on-disk version cannot be read for merging: "+p.loadErr.Error()),
+			"",
+			detailNoteStyle.Render("o overwrite it with your version  •  esc cancel"),
+		)
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, detailLabelStyle.Render("Your unsaved edits: ")+keyList(p.ours))
+	lines = append(lines, detailLabelStyle.Render("Changed on disk:    ")+keyList(p.theirs))
+	lines = append(lines, "")
+
+	if len(p.conflicts) == 0 {
+		lines = append(lines, detailDescStyle.Render("No conflicts — merging keeps both your edits and the on-disk changes."))
+	} else {
+		lines = append(lines, detailLabelStyle.Render("Conflicts (changed here and on disk):"))
+		for _, c := range p.conflicts {
+			lines = append(lines,
+				"  "+envVarNameStyle.Render(c.Key),
+				"    yours: "+conflictValue(c.Key, c.Ours, c.OursSet),
+				"    disk:  "+conflictValue(c.Key, c.Theirs, c.TheirsSet),
+			)
+		}
+	}
+
+	lines = append(lines, "", detailNoteStyle.Render("m merge (keep yours on conflicts)  •  t merge (keep disk on conflicts)  •  o overwrite  •  esc cancel"))
+	return strings.Join(lines, "\n")
+}
+
+// keyList renders a comma-separated key list or "(none)".
+func keyList(keys []string) string {
+	if len(keys) == 0 {
+		return detailNoteStyle.Render("(none)")
+	}
+	return strings.Join(keys, ", ")
+}
+
+// conflictValue renders one side of a conflict, masking sensitive values.
+func conflictValue(key string, value any, set bool) string {
+	if !set {
+		return detailNoteStyle.Render("(deleted)")
+	}
+	if isSensitiveValue(key, value) {
+		return sensitiveValueStyle.Render("🔒 redacted")
+	}
+	return detailValueStyle.Render(formatValueCompact(value, "", 60))
+}
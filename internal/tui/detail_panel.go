@@ -1,7 +1,9 @@
 package tui
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textarea"
@@ -9,6 +11,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jsburckhardt/co-config/internal/copilot"
 	"github.com/jsburckhardt/co-config/internal/sensitive"
+	"github.com/jsburckhardt/co-config/internal/validate"
 )
 
 // DetailPanel represents the right panel showing field details and editing widgets.
@@ -18,9 +21,15 @@ type DetailPanel struct {
 	isEditing     bool
 	textInput     textinput.Model
 	textArea      textarea.Model
+	listEditor    ListEditor
 	toggleValue   bool
 	selectIndex   int
 	validationErr string
+	invalidValue  any // the edited value validationErr is about
+	startValue    any
+	startText     string
+	showRawJSON   bool
+	envOverride   *copilot.EnvOverride
 	width         int
 	height        int
 }
@@ -32,21 +41,29 @@ func NewDetailPanel() DetailPanel {
 	ti.CharLimit = 500
 
 	ta := textarea.New()
-	ta.Placeholder = "Enter values, one per line..."
+	ta.Placeholder = "Enter a JSON value..."
 	ta.CharLimit = 5000
 	ta.SetHeight(5)
 
 	return DetailPanel{
-		textInput: ti,
-		textArea:  ta,
+		textInput:  ti,
+		textArea:   ta,
+		listEditor: NewListEditor(),
 	}
 }
 
+// SetEnvOverride shows that the current field is overridden by a set
+// environment variable; nil clears it.
+func (d *DetailPanel) SetEnvOverride(o *copilot.EnvOverride) {
+	d.envOverride = o
+}
+
 // SetField sets the current field to display/edit.
 func (d *DetailPanel) SetField(field copilot.SchemaField, value any) {
 	d.field = &field
 	d.value = value
 	d.validationErr = ""
+	d.showRawJSON = false
 
 	switch field.Type {
 	case "string":
@@ -59,15 +76,9 @@ func (d *DetailPanel) SetField(field copilot.SchemaField, value any) {
 			d.textInput.Width = d.width - 4
 		}
 	case "list":
-		var lines []string
-		if arr, ok := value.([]any); ok {
-			for _, item := range arr {
-				if s, ok := item.(string); ok {
-					lines = append(lines, s)
-				}
-			}
-		}
-		d.textArea.SetValue(strings.Join(lines, "\n"))
+		d.listEditor.SetItems(field.Name, value)
+	case "json":
+		d.textArea.SetValue(formatRawJSON(value))
 		if d.width > 4 {
 			d.textArea.SetWidth(d.width - 4)
 		}
@@ -96,18 +107,66 @@ func (d *DetailPanel) SetField(field copilot.SchemaField, value any) {
 func (d *DetailPanel) StartEditing() tea.Cmd {
 	d.isEditing = true
 	d.validationErr = ""
+	d.startValue = d.GetValue()
+	d.startText = d.textArea.Value()
 
 	switch d.field.Type {
 	case "string":
 		d.textInput.Focus()
 		return textinput.Blink
-	case "list":
+	case "json":
 		d.textArea.Focus()
 		return textarea.Blink
 	}
 	return nil
 }
 
+// Capturing reports whether the edit widget handles Esc itself, as the list
+// editor does while an item is being typed.
+func (d *DetailPanel) Capturing() bool {
+	return d.isEditing && d.field != nil && d.field.Type == "list" && d.listEditor.Inputting()
+}
+
+// Changed reports whether the editing widget holds a value that differs from
+// the one it started with, including JSON that does not parse yet.
+func (d *DetailPanel) Changed() bool {
+	if !d.isEditing || d.field == nil {
+		return false
+	}
+	if d.field.Type == "json" {
+		return d.textArea.Value() != d.startText
+	}
+	return !reflect.DeepEqual(d.GetValue(), d.startValue)
+}
+
+// ShowRawJSON toggles rendering of the stored JSON value below the current value.
+// It is reset by SetField.
+func (d *DetailPanel) ShowRawJSON(show bool) {
+	d.showRawJSON = show
+}
+
+// Validate checks that the value in the editing widget can be committed,
+// setting the inline validation error when it cannot.
+func (d *DetailPanel) Validate() bool {
+	d.validationErr = ""
+	if d.field == nil {
+		return true
+	}
+	d.invalidValue = d.editedValue()
+	if d.field.Type == "json" {
+		var v any
+		if err := json.Unmarshal([]byte(d.textArea.Value()), &v); err != nil {
+			d.validationErr = "invalid JSON: " + err.Error()
+			return false
+		}
+	}
+	if err := validate.Change(*d.field, d.value, d.GetValue()); err != nil {
+		d.validationErr = err.Error()
+		return false
+	}
+	return true
+}
+
 // StopEditing disables edit mode and returns the new value.
 func (d *DetailPanel) StopEditing() any {
 	d.isEditing = false
@@ -116,6 +175,30 @@ func (d *DetailPanel) StopEditing() any {
 	return d.GetValue()
 }
 
+// CancelEditing disables edit mode and resets the editing widget to the
+// stored value, discarding what was typed.
+func (d *DetailPanel) CancelEditing() {
+	d.StopEditing()
+	if d.field != nil {
+		d.SetField(*d.field, d.value)
+	}
+}
+
+// InvalidEdit reports whether the last attempt to commit failed validation
+// and the value was not changed since.
+func (d *DetailPanel) InvalidEdit() bool {
+	return d.isEditing && d.validationErr != "" && reflect.DeepEqual(d.editedValue(), d.invalidValue)
+}
+
+// editedValue returns the content of the editing widget, which for JSON
+// fields is the text, as it need not parse.
+func (d *DetailPanel) editedValue() any {
+	if d.field != nil && d.field.Type == "json" {
+		return d.textArea.Value()
+	}
+	return d.GetValue()
+}
+
 // GetValue returns the current value from the editing widget.
 func (d *DetailPanel) GetValue() any {
 	if d.field == nil {
@@ -132,15 +215,13 @@ func (d *DetailPanel) GetValue() any {
 		}
 		return d.field.Default
 	case "list":
-		lines := strings.Split(d.textArea.Value(), "\n")
-		var result []any
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line != "" {
-				result = append(result, line)
-			}
+		return d.listEditor.Items()
+	case "json":
+		var v any
+		if err := json.Unmarshal([]byte(d.textArea.Value()), &v); err != nil {
+			return d.value
 		}
-		return result
+		return v
 	}
 	return nil
 }
@@ -178,6 +259,8 @@ func (d *DetailPanel) Update(msg tea.Msg) tea.Cmd {
 	case "string":
 		d.textInput, cmd = d.textInput.Update(msg)
 	case "list":
+		cmd = d.listEditor.Update(msg)
+	case "json":
 		d.textArea, cmd = d.textArea.Update(msg)
 	}
 	return cmd
@@ -191,6 +274,8 @@ func (d *DetailPanel) SetSize(width, height int) {
 		d.textInput.Width = width - 4
 		d.textArea.SetWidth(width - 4)
 	}
+	// Leave room for the name, type, description and hints around the items.
+	d.listEditor.SetSize(width-4, height-16)
 }
 
 // CurrentFieldType returns the type of the currently loaded field, or "" if none.
@@ -223,14 +308,8 @@ func (d *DetailPanel) View() string {
 		b.WriteString("\n\n")
 	}
 
-	isSensitive := sensitive.IsSensitive(d.field.Name)
-	var isTokenLike bool
-	if strVal, ok := d.value.(string); ok {
-		isTokenLike = sensitive.LooksLikeToken(strVal)
-	}
-
 	switch {
-	case isSensitive || isTokenLike:
+	case isSensitiveValue(d.field.Name, d.value):
 		b.WriteString(detailLabelStyle.Render("Value (read-only):"))
 		b.WriteString("\n")
 		b.WriteString(sensitiveValueStyle.Render(sensitive.MaskValue(d.value)))
@@ -243,11 +322,41 @@ func (d *DetailPanel) View() string {
 		if d.validationErr != "" {
 			b.WriteString("\n")
 			b.WriteString(errorStyle.Render("⚠ " + d.validationErr))
+			if d.InvalidEdit() {
+				b.WriteString("\n")
+				b.WriteString(detailNoteStyle.Render("Press esc again to discard the edit."))
+			}
 		}
 	default:
 		b.WriteString(detailLabelStyle.Render("Current value:"))
 		b.WriteString("\n")
 		b.WriteString(d.renderCurrentValue())
+		if note := d.defaultNote(); note != "" {
+			b.WriteString("\n")
+			b.WriteString(detailNoteStyle.Render(note))
+		}
+
+		if o := d.envOverride; o != nil {
+			value := o.Value
+			if sensitive.IsEnvVarSensitive(o.Name) || sensitive.LooksLikeToken(value) {
+				value = sensitive.MaskValue(value)
+			}
+			b.WriteString("\n\n")
+			b.WriteString(errorStyle.Render(fmt.Sprintf("⚡ Overridden by %s=%s", o.Name, value)))
+			b.WriteString("\n")
+			note := "Copilot uses the environment variable, so changes here have no effect while it is set."
+			if len(o.Shadowed) > 0 {
+				note += " " + strings.Join(o.Shadowed, ", ") + " is also set but ignored, since the first name set takes precedence."
+			}
+			b.WriteString(detailNoteStyle.Render(note))
+		}
+
+		if d.showRawJSON {
+			b.WriteString("\n\n")
+			b.WriteString(detailLabelStyle.Render("Raw JSON:"))
+			b.WriteString("\n")
+			b.WriteString(detailValueStyle.Render(formatRawJSON(d.value)))
+		}
 
 		// Show options for enum fields
 		if d.field.Type == "enum" && len(d.field.Options) > 0 {
@@ -257,12 +366,29 @@ func (d *DetailPanel) View() string {
 		}
 
 		b.WriteString("\n\n")
-		b.WriteString(detailNoteStyle.Render("Press Enter to edit"))
+		b.WriteString(detailNoteStyle.Render(d.keyHints()))
 	}
 
 	return b.String()
 }
 
+// keyHints lists the keys that act on an editable field on one line. Keys
+// shown with the raw JSON are deleted rather than unset and can change their
+// edit type. Sensitive values are read-only and render no hints.
+func (d *DetailPanel) keyHints() string {
+	hints := []string{"Press Enter to edit"}
+	switch {
+	case d.value != nil && d.showRawJSON:
+		hints = append(hints, "d delete key")
+	case d.value != nil:
+		hints = append(hints, "d to unset")
+	}
+	if d.showRawJSON {
+		hints = append(hints, "t change edit type")
+	}
+	return strings.Join(hints, "  •  ")
+}
+
 func (d *DetailPanel) renderCurrentValue() string {
 	if d.value == nil && d.field != nil && d.field.Default != "" {
 		return detailValueStyle.Render(d.field.Default + " (default)")
@@ -270,6 +396,17 @@ func (d *DetailPanel) renderCurrentValue() string {
 	return detailValueStyle.Render(formatValueDetail(d.value))
 }
 
+// defaultNote explains whether the field is unset or explicitly set to its default.
+func (d *DetailPanel) defaultNote() string {
+	switch {
+	case d.value == nil && d.field.Default != "":
+		return "Not set in this scope; Copilot uses its default."
+	case d.value != nil && isDefaultValue(*d.field, d.value):
+		return "Set explicitly to the default value; unset it to follow future default changes."
+	}
+	return ""
+}
+
 func (d *DetailPanel) renderEditWidget() string {
 	switch d.field.Type {
 	case "string":
@@ -291,11 +428,28 @@ func (d *DetailPanel) renderEditWidget() string {
 		}
 		return opts.String()
 	case "list":
+		return d.listEditor.View()
+	case "json":
 		return d.textArea.View()
 	}
 	return ""
 }
 
+// isMultilineType reports whether a field type is edited with a multi-line
+// widget, where Enter edits within the widget instead of committing.
+func isMultilineType(fieldType string) bool {
+	return fieldType == "list" || fieldType == "json"
+}
+
+// formatRawJSON renders a value as indented JSON.
+func formatRawJSON(val any) string {
+	data, err := json.MarshalIndent(val, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return string(data)
+}
+
 func formatValueDetail(val any) string {
 	switch v := val.(type) {
 	case string:
@@ -313,6 +467,8 @@ func formatValueDetail(val any) string {
 		for _, item := range v {
 			if s, ok := item.(string); ok {
 				strs = append(strs, s)
+			} else if data, err := json.Marshal(item); err == nil {
+				strs = append(strs, string(data))
 			}
 		}
 		return strings.Join(strs, "\n")
@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+	"github.com/jsburckhardt/co-config/internal/sensitive"
+)
+
+// effectiveLinesPerEntry is the number of rendered lines each effective value occupies.
+const effectiveLinesPerEntry = 3
+
+// EffectivePanel displays the merged configuration across all scopes in a
+// scrollable, read-only view, showing which scope file supplied each value.
+type EffectivePanel struct {
+	values []config.EffectiveValue
+	paths  map[config.Scope]string
+	cursor int
+	offset int
+	width  int
+	height int
+}
+
+// NewEffectivePanel creates a new effective config panel.
+func NewEffectivePanel(values []config.EffectiveValue, paths map[config.Scope]string) *EffectivePanel {
+	return &EffectivePanel{values: values, paths: paths}
+}
+
+// SetSize updates the panel content dimensions.
+func (p *EffectivePanel) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+	p.ensureVisible()
+}
+
+// Up moves cursor up one entry.
+func (p *EffectivePanel) Up() {
+	if p.cursor > 0 {
+		p.cursor--
+		p.ensureVisible()
+	}
+}
+
+// Down moves cursor down one entry.
+func (p *EffectivePanel) Down() {
+	if p.cursor < len(p.values)-1 {
+		p.cursor++
+		p.ensureVisible()
+	}
+}
+
+// visibleEntries returns how many entries fit below the title line.
+func (p *EffectivePanel) visibleEntries() int {
+	visible := (p.height - 2) / effectiveLinesPerEntry
+	if visible < 1 {
+		visible = 1
+	}
+	return visible
+}
+
+// ensureVisible adjusts offset so the cursor is within the visible viewport.
+func (p *EffectivePanel) ensureVisible() {
+	if p.height <= 0 || len(p.values) == 0 {
+		return
+	}
+	visible := p.visibleEntries()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+visible {
+		p.offset = p.cursor - visible + 1
+	}
+}
+
+// View renders the panel content.
+func (p *EffectivePanel) View() string {
+	if p.width <= 0 || p.height <= 0 {
+		return ""
+	}
+
+	lines := []string{
+		detailHeaderStyle.Render("Effective configuration"),
+		detailNoteStyle.Render("user → project → local; later scopes win"),
+	}
+
+	if len(p.values) == 0 {
+		lines = append(lines, "", envVarDescStyle.Render("No keys are set in any scope"))
+	}
+
+	end := p.offset + p.visibleEntries()
+	if end > len(p.values) {
+		end = len(p.values)
+	}
+	for i := p.offset; i < end; i++ {
+		lines = append(lines, p.renderEntry(p.values[i], i == p.cursor)...)
+	}
+
+	for len(lines) < p.height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderEntry renders a single effective value as multiple lines.
+func (p *EffectivePanel) renderEntry(ev config.EffectiveValue, selected bool) []string {
+	var value string
+	if isSensitiveValue(ev.Key, ev.Value) {
+		value = envVarSensitiveStyle.Render("🔒 " + sensitive.MaskValue(ev.Value))
+	} else {
+		valWidth := p.width - len(ev.Key) - 6
+		value = envVarValueSetStyle.Render(formatValueCompact(ev.Value, "", valWidth))
+	}
+
+	prefix := "  "
+	if selected {
+		prefix = "▶ "
+	}
+	nameLine := fmt.Sprintf("%s%s  %s", prefix, envVarNameStyle.Render(ev.Key), value)
+
+	source := "from " + ev.Scope.Label()
+	if path := p.paths[ev.Scope]; path != "" {
+		source += " (" + path + ")"
+	}
+	if len(ev.Overrides) > 0 {
+		var labels []string
+		for _, s := range ev.Overrides {
+			labels = append(labels, s.Label())
+		}
+		source += " · overrides " + strings.Join(labels, ", ")
+	}
+
+	return []string{nameLine, envVarQualifierStyle.Render("    " + source), ""}
+}
+
+// isSensitiveValue reports whether a key/value pair must be masked,
+// including token-like strings nested in objects and lists.
+func isSensitiveValue(key string, value any) bool {
+	return config.IsSensitiveEntry(key, value)
+}
@@ -0,0 +1,101 @@
+package tui
+
+// maxHistory bounds the number of undoable edits kept in memory.
+const maxHistory = 100
+
+// fieldEdit records one change to a config key so it can be undone.
+// A value of nil with set=false means the key was absent.
+type fieldEdit struct {
+	key         string
+	before      any
+	beforeSet   bool
+	after       any
+	afterSet    bool
+	wasModified bool // the row's "(not-saved)" marker before the edit
+}
+
+// editHistory is a linear undo/redo stack. Each step holds the field edits
+// made by one action, which are undone and redone together.
+type editHistory struct {
+	undo [][]fieldEdit
+	redo [][]fieldEdit
+}
+
+// record pushes the edits of one action as a single step and discards
+// anything that could be redone.
+func (h *editHistory) record(edits ...fieldEdit) {
+	if len(edits) == 0 {
+		return
+	}
+	h.undo = append(h.undo, edits)
+	if len(h.undo) > maxHistory {
+		h.undo = h.undo[len(h.undo)-maxHistory:]
+	}
+	h.redo = nil
+}
+
+// popUndo removes the most recent step and moves it to the redo stack.
+func (h *editHistory) popUndo() ([]fieldEdit, bool) {
+	if len(h.undo) == 0 {
+		return nil, false
+	}
+	e := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, e)
+	return e, true
+}
+
+// popRedo removes the most recently undone step and moves it back to the undo stack.
+func (h *editHistory) popRedo() ([]fieldEdit, bool) {
+	if len(h.redo) == 0 {
+		return nil, false
+	}
+	e := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, e)
+	return e, true
+}
+
+// clear forgets all edits, e.g. after they were saved or the scope changed.
+func (h *editHistory) clear() {
+	h.undo = nil
+	h.redo = nil
+}
+
+// rebase makes the oldest undoable edit of key start from value, e.g. after
+// the file was reloaded with a new value under an unsaved edit. Undoing that
+// edit then restores the reloaded value and clears the row's marker.
+func (h *editHistory) rebase(key string, value any, set bool) {
+	for _, step := range h.undo {
+		for i := range step {
+			if step[i].key == key {
+				step[i].before = value
+				step[i].beforeSet = set
+				step[i].wasModified = false
+				return
+			}
+		}
+	}
+}
+
+// drop forgets every edit of key, removing steps left without edits.
+func (h *editHistory) drop(key string) {
+	h.undo = dropKey(h.undo, key)
+	h.redo = dropKey(h.redo, key)
+}
+
+func dropKey(steps [][]fieldEdit, key string) [][]fieldEdit {
+	var out [][]fieldEdit
+	for _, step := range steps {
+		var kept []fieldEdit
+		for _, e := range step {
+			if e.key != key {
+				kept = append(kept, e)
+			}
+		}
+		if len(kept) > 0 {
+			out = append(out, kept)
+		}
+	}
+	return out
+}
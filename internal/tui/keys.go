@@ -4,18 +4,48 @@ import "github.com/charmbracelet/bubbles/key"
 
 // KeyMap defines the key bindings for the TUI.
 type KeyMap struct {
-	Up          key.Binding
-	Down        key.Binding
-	Left        key.Binding
-	Right       key.Binding
-	Enter       key.Binding
-	Confirm     key.Binding
-	Escape      key.Binding
-	Save        key.Binding
-	Quit        key.Binding
-	Tab         key.Binding
-	Filter      key.Binding
-	ScopeSwitch key.Binding
+	Up            key.Binding
+	Down          key.Binding
+	Left          key.Binding
+	Right         key.Binding
+	Enter         key.Binding
+	Confirm       key.Binding
+	Escape        key.Binding
+	Save          key.Binding
+	Quit          key.Binding
+	Tab           key.Binding
+	Filter        key.Binding
+	ScopeSwitch   key.Binding
+	Effective     key.Binding
+	Backups       key.Binding
+	Restore       key.Binding
+	Merge         key.Binding
+	MergeTheirs   key.Binding
+	Overwrite     key.Binding
+	Cancel        key.Binding
+	ConfirmSave   key.Binding
+	SaveFirst     key.Binding
+	Discard       key.Binding
+	Profiles      key.Binding
+	Apply         key.Binding
+	Undo          key.Binding
+	Redo          key.Binding
+	Migrate       key.Binding
+	Skip          key.Binding
+	ListAdd       key.Binding
+	ListEdit      key.Binding
+	ListRemove    key.Binding
+	ListMove      key.Binding
+	ListSort      key.Binding
+	ListDedupe    key.Binding
+	NextMatch     key.Binding
+	Unset         key.Binding
+	ResetCategory key.Binding
+	Compare       key.Binding
+	CompareCopy   key.Binding
+	CompareMove   key.Binding
+	CompareScopes key.Binding
+	MoveSecrets   key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings.
@@ -69,5 +99,125 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("S"),
 			key.WithHelp("S", "scope"),
 		),
+		Effective: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "effective"),
+		),
+		Backups: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "backups"),
+		),
+		Restore: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "restore"),
+		),
+		Merge: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "merge (mine)"),
+		),
+		MergeTheirs: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "merge (disk)"),
+		),
+		Overwrite: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "overwrite"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+		ConfirmSave: key.NewBinding(
+			key.WithKeys("enter", "y"),
+			key.WithHelp("enter/y", "save"),
+		),
+		SaveFirst: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "save"),
+		),
+		Discard: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "discard"),
+		),
+		Profiles: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "profiles"),
+		),
+		Apply: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "apply"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo"),
+		),
+		Redo: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "redo"),
+		),
+		Migrate: key.NewBinding(
+			key.WithKeys("enter", "y"),
+			key.WithHelp("enter/y", "migrate"),
+		),
+		Skip: key.NewBinding(
+			key.WithKeys("esc", "n"),
+			key.WithHelp("esc", "skip"),
+		),
+		ListAdd: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "add"),
+		),
+		ListEdit: key.NewBinding(
+			key.WithKeys("e", "enter"),
+			key.WithHelp("e", "edit item"),
+		),
+		ListRemove: key.NewBinding(
+			key.WithKeys("x", "delete"),
+			key.WithHelp("x", "remove"),
+		),
+		ListMove: key.NewBinding(
+			key.WithKeys("K", "J", "shift+up", "shift+down"),
+			key.WithHelp("K/J", "move"),
+		),
+		ListSort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort"),
+		),
+		ListDedupe: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "dedupe"),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("tab", "shift+tab"),
+			key.WithHelp("tab", "next match"),
+		),
+		Unset: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "unset"),
+		),
+		ResetCategory: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "reset category"),
+		),
+		Compare: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "compare"),
+		),
+		CompareCopy: key.NewBinding(
+			key.WithKeys("left", "right", "h", "l"),
+			key.WithHelp("←/→", "copy value"),
+		),
+		CompareMove: key.NewBinding(
+			key.WithKeys("<", ">", "shift+left", "shift+right"),
+			key.WithHelp("</>", "move value"),
+		),
+		CompareScopes: key.NewBinding(
+			key.WithKeys("[", "]"),
+			key.WithHelp("[/]", "change scopes"),
+		),
+		MoveSecrets: key.NewBinding(
+			key.WithKeys("enter", "y"),
+			key.WithHelp("enter/y", "move to local"),
+		),
 	}
 }
@@ -0,0 +1,382 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jsburckhardt/co-config/internal/validate"
+)
+
+// ListEditor edits a list value item by item. String items can be added,
+// edited, removed, reordered and sorted; items of other JSON types cannot be
+// edited but are shown and kept in place.
+type ListEditor struct {
+	name      string
+	items     []any
+	cursor    int
+	offset    int
+	input     textinput.Model
+	inputting bool
+	editIndex int // item being edited, or -1 while adding
+	inputErr  string
+	status    string
+	width     int
+	height    int
+}
+
+// NewListEditor creates an empty list editor.
+func NewListEditor() ListEditor {
+	ti := textinput.New()
+	ti.Placeholder = "New item (paste many lines to add several)"
+	ti.CharLimit = 2000
+	return ListEditor{input: ti, editIndex: -1, height: 8}
+}
+
+// SetItems loads the list of the named field. Values that are not lists start empty.
+func (e *ListEditor) SetItems(name string, value any) {
+	e.name = name
+	e.items = nil
+	if arr, ok := value.([]any); ok {
+		e.items = append([]any(nil), arr...)
+	}
+	e.cursor, e.offset = 0, 0
+	e.stopInput()
+	e.status = ""
+}
+
+// Items returns the edited list. An empty list is returned as an empty,
+// non-nil slice so it is saved as [] rather than null.
+func (e *ListEditor) Items() []any {
+	return append([]any{}, e.items...)
+}
+
+// Inputting reports whether an item is being typed, in which case Esc
+// cancels the input instead of finishing the edit.
+func (e *ListEditor) Inputting() bool {
+	return e.inputting
+}
+
+// SetSize sets the width and the number of visible item rows.
+func (e *ListEditor) SetSize(width, height int) {
+	e.width = width
+	if height < 3 {
+		height = 3
+	}
+	e.height = height
+	if width > 4 {
+		e.input.Width = width - 4
+	}
+	e.ensureVisible()
+}
+
+// Update handles a key press.
+func (e *ListEditor) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if e.inputting {
+			var cmd tea.Cmd
+			e.input, cmd = e.input.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+	// Pasting while browsing the items, or pasting several lines into the
+	// input, adds one item per line.
+	if keyMsg.Paste && (!e.inputting || strings.ContainsAny(string(keyMsg.Runes), "\r\n")) {
+		e.addMany(string(keyMsg.Runes))
+		return nil
+	}
+	if e.inputting {
+		return e.updateInput(keyMsg)
+	}
+
+	e.status = ""
+	switch keyMsg.String() {
+	case "up", "k":
+		if e.cursor > 0 {
+			e.cursor--
+		}
+	case "down", "j":
+		if e.cursor < len(e.items)-1 {
+			e.cursor++
+		}
+	case "a":
+		e.editIndex = -1
+		e.input.SetValue("")
+		return e.startInput()
+	case "e", "enter":
+		if e.cursor >= len(e.items) {
+			break
+		}
+		s, ok := e.items[e.cursor].(string)
+		if !ok {
+			e.status = "only text items can be edited"
+			break
+		}
+		e.editIndex = e.cursor
+		e.input.SetValue(s)
+		e.input.CursorEnd()
+		return e.startInput()
+	case "x", "delete":
+		if e.cursor < len(e.items) {
+			e.items = append(e.items[:e.cursor], e.items[e.cursor+1:]...)
+			if e.cursor >= len(e.items) && e.cursor > 0 {
+				e.cursor--
+			}
+		}
+	case "K", "shift+up":
+		if e.cursor > 0 && e.cursor < len(e.items) {
+			e.items[e.cursor-1], e.items[e.cursor] = e.items[e.cursor], e.items[e.cursor-1]
+			e.cursor--
+		}
+	case "J", "shift+down":
+		if e.cursor < len(e.items)-1 {
+			e.items[e.cursor+1], e.items[e.cursor] = e.items[e.cursor], e.items[e.cursor+1]
+			e.cursor++
+		}
+	case "s":
+		e.sortItems()
+		e.status = "sorted"
+	case "d":
+		if n := e.removeDuplicates(); n > 0 {
+			e.status = fmt.Sprintf("removed %d duplicate(s)", n)
+		} else {
+			e.status = "no duplicates"
+		}
+	}
+	e.ensureVisible()
+	return nil
+}
+
+// updateInput handles a key press while an item is being typed.
+func (e *ListEditor) updateInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		e.stopInput()
+		return nil
+	case "enter":
+		e.commitInput()
+		return nil
+	}
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	return cmd
+}
+
+// commitInput adds or replaces the typed item. An empty input cancels; an
+// invalid or duplicate item keeps the input open with the reason shown.
+func (e *ListEditor) commitInput() {
+	s := strings.TrimSpace(e.input.Value())
+	if s == "" {
+		e.stopInput()
+		return
+	}
+	if err := validate.Item(e.name, s); err != nil {
+		e.inputErr = err.Error()
+		return
+	}
+	if i := e.indexOf(s); i >= 0 && i != e.editIndex {
+		e.inputErr = fmt.Sprintf("%q is already item %d", s, i+1)
+		return
+	}
+	if e.editIndex >= 0 {
+		e.items[e.editIndex] = s
+	} else {
+		e.insert(s)
+	}
+	e.stopInput()
+	e.ensureVisible()
+}
+
+// addMany adds each non-empty line of text after the cursor, skipping
+// duplicates. Invalid items are added and flagged so they can be fixed.
+func (e *ListEditor) addMany(text string) {
+	added, skipped := 0, 0
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if e.indexOf(line) >= 0 {
+			skipped++
+			continue
+		}
+		e.insert(line)
+		added++
+	}
+	e.stopInput()
+	e.status = fmt.Sprintf("added %d item(s)", added)
+	if skipped > 0 {
+		e.status += fmt.Sprintf(", skipped %d duplicate(s)", skipped)
+	}
+	e.ensureVisible()
+}
+
+// insert adds an item after the cursor and selects it.
+func (e *ListEditor) insert(item any) {
+	at := 0
+	if len(e.items) > 0 {
+		at = e.cursor + 1
+	}
+	e.items = append(e.items, nil)
+	copy(e.items[at+1:], e.items[at:])
+	e.items[at] = item
+	e.cursor = at
+}
+
+// sortItems sorts the text items alphabetically. Items of other types keep
+// their positions.
+func (e *ListEditor) sortItems() {
+	var slots []int
+	var strs []string
+	for i, item := range e.items {
+		if s, ok := item.(string); ok {
+			slots = append(slots, i)
+			strs = append(strs, s)
+		}
+	}
+	sort.SliceStable(strs, func(i, j int) bool { return strings.ToLower(strs[i]) < strings.ToLower(strs[j]) })
+	for i, slot := range slots {
+		e.items[slot] = strs[i]
+	}
+}
+
+// removeDuplicates keeps the first of each set of equal items and returns
+// how many were removed.
+func (e *ListEditor) removeDuplicates() int {
+	kept := make([]any, 0, len(e.items))
+	for i, item := range e.items {
+		if e.duplicateOf(i) < 0 {
+			kept = append(kept, item)
+		}
+	}
+	n := len(e.items) - len(kept)
+	e.items = kept
+	if e.cursor >= len(e.items) {
+		e.cursor = max(len(e.items)-1, 0)
+	}
+	return n
+}
+
+// duplicateOf returns the index of the first earlier item equal to item i, or -1.
+func (e *ListEditor) duplicateOf(i int) int {
+	for j := 0; j < i; j++ {
+		if reflect.DeepEqual(e.items[j], e.items[i]) {
+			return j
+		}
+	}
+	return -1
+}
+
+// indexOf returns the index of the text item s, or -1.
+func (e *ListEditor) indexOf(s string) int {
+	for i, item := range e.items {
+		if v, ok := item.(string); ok && v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func (e *ListEditor) startInput() tea.Cmd {
+	e.inputting = true
+	e.inputErr = ""
+	return e.input.Focus()
+}
+
+func (e *ListEditor) stopInput() {
+	e.inputting = false
+	e.inputErr = ""
+	e.editIndex = -1
+	e.input.Blur()
+}
+
+func (e *ListEditor) ensureVisible() {
+	if e.cursor < e.offset {
+		e.offset = e.cursor
+	}
+	if e.cursor >= e.offset+e.height {
+		e.offset = e.cursor - e.height + 1
+	}
+}
+
+// View renders the items with per-item problems, the input line and the status.
+func (e *ListEditor) View() string {
+	var b strings.Builder
+	if len(e.items) == 0 {
+		b.WriteString(detailNoteStyle.Render("(empty)"))
+		b.WriteString("\n")
+	}
+	end := min(e.offset+e.height, len(e.items))
+	if e.offset > 0 {
+		b.WriteString(detailNoteStyle.Render(fmt.Sprintf("  ↑ %d more", e.offset)))
+		b.WriteString("\n")
+	}
+	for i := e.offset; i < end; i++ {
+		line := fmt.Sprintf("%d. %s", i+1, e.itemText(i))
+		if i == e.cursor && !(e.inputting && e.editIndex < 0) {
+			b.WriteString(selectedOptionStyle.Render("▶ " + line))
+		} else {
+			b.WriteString(optionStyle.Render("  " + line))
+		}
+		if problem := e.itemProblem(i); problem != "" {
+			b.WriteString(" ")
+			b.WriteString(errorStyle.Render("⚠ " + problem))
+		}
+		b.WriteString("\n")
+	}
+	if end < len(e.items) {
+		b.WriteString(detailNoteStyle.Render(fmt.Sprintf("  ↓ %d more", len(e.items)-end)))
+		b.WriteString("\n")
+	}
+
+	if e.inputting {
+		label := "Add item:"
+		if e.editIndex >= 0 {
+			label = fmt.Sprintf("Edit item %d:", e.editIndex+1)
+		}
+		b.WriteString("\n")
+		b.WriteString(detailLabelStyle.Render(label))
+		b.WriteString("\n")
+		b.WriteString(e.input.View())
+		if e.inputErr != "" {
+			b.WriteString("\n")
+			b.WriteString(errorStyle.Render("⚠ " + e.inputErr))
+		}
+		b.WriteString("\n")
+		b.WriteString(detailNoteStyle.Render("enter confirm  •  esc cancel"))
+	} else if e.status != "" {
+		b.WriteString(detailNoteStyle.Render(e.status))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// itemText renders item i; items that are not text are shown as JSON.
+func (e *ListEditor) itemText(i int) string {
+	if s, ok := e.items[i].(string); ok {
+		return s
+	}
+	data, err := json.Marshal(e.items[i])
+	if err != nil {
+		return fmt.Sprintf("%v", e.items[i])
+	}
+	return string(data) + " (read-only)"
+}
+
+// itemProblem describes why item i would be rejected, or returns "".
+func (e *ListEditor) itemProblem(i int) string {
+	if j := e.duplicateOf(i); j >= 0 {
+		return fmt.Sprintf("duplicate of %d", j+1)
+	}
+	if s, ok := e.items[i].(string); ok {
+		if err := validate.Item(e.name, s); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
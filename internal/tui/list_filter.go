@@ -0,0 +1,246 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StartFilter focuses the filter input, keeping the current filter text.
+func (l *ListPanel) StartFilter() tea.Cmd {
+	l.filtering = true
+	l.filterInput.SetValue(l.filter)
+	l.filterInput.CursorEnd()
+	l.ensureVisible()
+	return l.filterInput.Focus()
+}
+
+// StopFilter leaves the filter input. With keep unset the filter is cleared.
+func (l *ListPanel) StopFilter(keep bool) {
+	l.filtering = false
+	l.filterInput.Blur()
+	if !keep {
+		l.SetFilter("")
+	}
+	l.ensureVisible()
+}
+
+// UpdateFilter passes a message to the filter input and refilters as the query changes.
+func (l *ListPanel) UpdateFilter(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	l.filterInput, cmd = l.filterInput.Update(msg)
+	if q := l.filterInput.Value(); q != l.filter {
+		l.SetFilter(q)
+	}
+	return cmd
+}
+
+// Filter returns the active filter query.
+func (l *ListPanel) Filter() string {
+	return l.filter
+}
+
+// SetFilter shows only the fields matching query, with the headers of the
+// groups that still have fields. If the selected field is hidden, the cursor
+// moves to the first match.
+func (l *ListPanel) SetFilter(query string) {
+	l.filter = strings.TrimSpace(query)
+	l.filterInput.SetValue(query)
+	l.rebuildView()
+	if l.position() < 0 || l.cursor < 0 || l.entries[l.cursor].isHeader {
+		l.cursor = -1
+		for _, i := range l.view {
+			if !l.entries[i].isHeader {
+				l.cursor = i
+				break
+			}
+		}
+	}
+	l.offset = 0
+	l.ensureVisible()
+}
+
+// MatchCount returns the number of fields shown by the filter.
+func (l *ListPanel) MatchCount() int {
+	n := 0
+	for _, i := range l.view {
+		if !l.entries[i].isHeader {
+			n++
+		}
+	}
+	return n
+}
+
+// NextMatch moves to the next shown field, wrapping around at the end.
+func (l *ListPanel) NextMatch() {
+	before := l.cursor
+	l.Down()
+	if l.cursor == before {
+		l.cursor = -1
+		l.Down()
+		if l.cursor < 0 {
+			l.cursor = before
+		}
+	}
+}
+
+// PrevMatch moves to the previous shown field, wrapping around at the start.
+func (l *ListPanel) PrevMatch() {
+	before := l.cursor
+	l.Up()
+	if l.cursor == before && len(l.view) > 0 {
+		last := l.view[len(l.view)-1]
+		if !l.entries[last].isHeader {
+			l.cursor = last
+			l.ensureVisible()
+		}
+	}
+}
+
+// rebuildView recomputes the shown entries and match positions for the filter.
+func (l *ListPanel) rebuildView() {
+	l.view = l.view[:0]
+	l.matches = nil
+	if l.filter == "" {
+		for i := range l.entries {
+			l.view = append(l.view, i)
+		}
+		return
+	}
+	l.matches = make(map[int][]int)
+	header := -1
+	for i, e := range l.entries {
+		if e.isHeader {
+			header = i
+			continue
+		}
+		positions, ok := matchItem(e.item, l.filter)
+		if !ok {
+			continue
+		}
+		if header >= 0 {
+			l.view = append(l.view, header)
+			header = -1
+		}
+		l.view = append(l.view, i)
+		if len(positions) > 0 {
+			l.matches[i] = positions
+		}
+	}
+}
+
+// matchItem matches query against a field: fuzzily against the name, and as
+// a case-insensitive substring of the description or the current value. It
+// returns the matched positions in the name, if the name matched. Values of
+// sensitive fields are never searched.
+func matchItem(item ConfigItem, query string) ([]int, bool) {
+	if positions, ok := fuzzyMatch(query, item.Field.Name); ok {
+		return positions, true
+	}
+	q := strings.ToLower(query)
+	if strings.Contains(strings.ToLower(item.Field.Description), q) {
+		return nil, true
+	}
+	if item.Value == nil || isSensitiveItem(item) {
+		return nil, false
+	}
+	return nil, strings.Contains(strings.ToLower(formatValueDetail(item.Value)), q)
+}
+
+// fuzzyMatch reports whether the runes of pattern appear in s in order,
+// ignoring case and spaces in pattern, and returns their rune positions in s.
+// Matches at the start of a word are preferred over earlier ones.
+func fuzzyMatch(pattern, s string) ([]int, bool) {
+	var pat []rune
+	for _, r := range strings.ToLower(pattern) {
+		if !unicode.IsSpace(r) {
+			pat = append(pat, r)
+		}
+	}
+	if len(pat) == 0 {
+		return nil, false
+	}
+	runes := []rune(strings.ToLower(s))
+	positions := make([]int, 0, len(pat))
+	j := 0
+	for i := 0; i < len(runes) && j < len(pat); i++ {
+		if runes[i] != pat[j] {
+			continue
+		}
+		// Skip ahead to a word start that matches the same rune, if there is one
+		// before the next pattern rune is needed.
+		if i > 0 && isWordRune(runes[i-1]) {
+			for k := i + 1; k < len(runes); k++ {
+				if runes[k] == pat[j] && !isWordRune(runes[k-1]) && canFinish(runes[k+1:], pat[j+1:]) {
+					i = k
+					break
+				}
+			}
+		}
+		positions = append(positions, i)
+		j++
+	}
+	if j < len(pat) {
+		return nil, false
+	}
+	return positions, true
+}
+
+// canFinish reports whether pat is a subsequence of runes.
+func canFinish(runes, pat []rune) bool {
+	j := 0
+	for i := 0; i < len(runes) && j < len(pat); i++ {
+		if runes[i] == pat[j] {
+			j++
+		}
+	}
+	return j == len(pat)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// highlightRunes renders s with style, underlining the runes at positions.
+func highlightRunes(s string, positions []int, style lipgloss.Style) string {
+	match := style.Underline(true).Bold(true)
+	hit := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		hit[p] = true
+	}
+	var b strings.Builder
+	var run []rune
+	inMatch := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if inMatch {
+			b.WriteString(match.Render(string(run)))
+		} else {
+			b.WriteString(style.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(s) {
+		if hit[i] != inMatch {
+			flush()
+			inMatch = hit[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
+
+// filterLine renders the filter input, or the applied filter and its match count.
+func (l *ListPanel) filterLine() string {
+	count := detailNoteStyle.Render(fmt.Sprintf("  %d match(es)", l.MatchCount()))
+	if l.filtering {
+		return l.filterInput.View() + count
+	}
+	return selectedOptionStyle.Render("/"+l.filter) + count + detailNoteStyle.Render("  esc clear")
+}
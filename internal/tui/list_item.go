@@ -2,10 +2,12 @@ package tui
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/jsburckhardt/co-config/internal/copilot"
-	"github.com/jsburckhardt/co-config/internal/sensitive"
 )
 
 // ConfigItem represents a config field in the list.
@@ -13,6 +15,8 @@ type ConfigItem struct {
 	Field    copilot.SchemaField
 	Value    any
 	Modified bool
+	// Unlisted is true for keys present in the config but not in the detected schema.
+	Unlisted bool
 }
 
 type listEntry struct {
@@ -28,11 +32,24 @@ type ListPanel struct {
 	offset  int
 	width   int
 	height  int
+	// envOverrides marks fields overridden by a set environment variable.
+	envOverrides map[string]copilot.EnvOverride
+	// view holds the indexes of the shown entries: all of them unless a
+	// filter is set. offset is a position in view.
+	view        []int
+	filter      string
+	matches     map[int][]int // entry index → matched rune positions in the name
+	filterInput textinput.Model
+	filtering   bool
 }
 
 // NewListPanel creates a list panel with cursor on the first ConfigItem.
 func NewListPanel(entries []listEntry) *ListPanel {
-	lp := &ListPanel{entries: entries, cursor: -1}
+	fi := textinput.New()
+	fi.Prompt = "/"
+	fi.Placeholder = "filter by name, description or value"
+	lp := &ListPanel{entries: entries, cursor: -1, filterInput: fi}
+	lp.rebuildView()
 	for i, e := range entries {
 		if !e.isHeader {
 			lp.cursor = i
@@ -42,6 +59,11 @@ func NewListPanel(entries []listEntry) *ListPanel {
 	return lp
 }
 
+// SetEnvOverrides marks the fields overridden by set environment variables.
+func (l *ListPanel) SetEnvOverrides(overrides map[string]copilot.EnvOverride) {
+	l.envOverrides = overrides
+}
+
 func (l *ListPanel) SetSize(w, h int) {
 	l.width = w
 	l.height = h
@@ -49,8 +71,8 @@ func (l *ListPanel) SetSize(w, h int) {
 }
 
 func (l *ListPanel) Up() {
-	for i := l.cursor - 1; i >= 0; i-- {
-		if !l.entries[i].isHeader {
+	for p := l.position() - 1; p >= 0; p-- {
+		if i := l.view[p]; !l.entries[i].isHeader {
 			l.cursor = i
 			l.ensureVisible()
 			return
@@ -59,8 +81,8 @@ func (l *ListPanel) Up() {
 }
 
 func (l *ListPanel) Down() {
-	for i := l.cursor + 1; i < len(l.entries); i++ {
-		if !l.entries[i].isHeader {
+	for p := l.position() + 1; p < len(l.view); p++ {
+		if i := l.view[p]; !l.entries[i].isHeader {
 			l.cursor = i
 			l.ensureVisible()
 			return
@@ -68,6 +90,27 @@ func (l *ListPanel) Down() {
 	}
 }
 
+// Select moves the cursor to the entry with the given name, clearing the
+// filter if it hides the entry.
+func (l *ListPanel) Select(fieldName string) bool {
+	for i, e := range l.entries {
+		if !e.isHeader && e.item.Field.Name == fieldName {
+			if !slices.Contains(l.view, i) {
+				l.SetFilter("")
+			}
+			l.cursor = i
+			l.ensureVisible()
+			return true
+		}
+	}
+	return false
+}
+
+// position returns the cursor's position in view, or -1.
+func (l *ListPanel) position() int {
+	return slices.Index(l.view, l.cursor)
+}
+
 func (l *ListPanel) SelectedItem() *ConfigItem {
 	if l.cursor >= 0 && l.cursor < len(l.entries) && !l.entries[l.cursor].isHeader {
 		item := l.entries[l.cursor].item
@@ -86,6 +129,71 @@ func (l *ListPanel) UpdateItemValue(fieldName string, newValue any) {
 	}
 }
 
+// UpdateItemField replaces the schema field of the entry with the same name.
+func (l *ListPanel) UpdateItemField(field copilot.SchemaField) {
+	for i, e := range l.entries {
+		if !e.isHeader && e.item.Field.Name == field.Name {
+			l.entries[i].item.Field = field
+			break
+		}
+	}
+}
+
+// GroupItems returns the header and items of the group containing the entry
+// with the given name.
+func (l *ListPanel) GroupItems(fieldName string) (string, []ConfigItem) {
+	header, start := "", 0
+	for i, e := range l.entries {
+		if e.isHeader {
+			header, start = e.header, i+1
+			continue
+		}
+		if e.item.Field.Name != fieldName {
+			continue
+		}
+		var items []ConfigItem
+		for _, g := range l.entries[start:] {
+			if g.isHeader {
+				break
+			}
+			items = append(items, g.item)
+		}
+		return header, items
+	}
+	return "", nil
+}
+
+// HasItem reports whether there is an entry with the given name.
+func (l *ListPanel) HasItem(fieldName string) bool {
+	for _, e := range l.entries {
+		if !e.isHeader && e.item.Field.Name == fieldName {
+			return true
+		}
+	}
+	return false
+}
+
+// SetItemModified sets the Modified flag of the entry with the given name.
+func (l *ListPanel) SetItemModified(fieldName string, modified bool) {
+	for i, e := range l.entries {
+		if !e.isHeader && e.item.Field.Name == fieldName {
+			l.entries[i].item.Modified = modified
+			break
+		}
+	}
+}
+
+// ModifiedNames returns the names of entries with unsaved changes, in list order.
+func (l *ListPanel) ModifiedNames() []string {
+	var names []string
+	for _, e := range l.entries {
+		if !e.isHeader && e.item.Modified {
+			names = append(names, e.item.Field.Name)
+		}
+	}
+	return names
+}
+
 // ClearAllModified resets the Modified flag on all entries.
 func (l *ListPanel) ClearAllModified() {
 	for i := range l.entries {
@@ -94,32 +202,49 @@ func (l *ListPanel) ClearAllModified() {
 }
 
 func (l *ListPanel) ensureVisible() {
-	if l.height <= 0 || l.cursor < 0 {
+	rows := l.rows()
+	if l.offset > max(len(l.view)-rows, 0) {
+		l.offset = max(len(l.view)-rows, 0)
+	}
+	p := l.position()
+	if rows <= 0 || p < 0 {
 		return
 	}
-	if l.cursor < l.offset {
-		l.offset = l.cursor
-		if l.offset > 0 && l.entries[l.offset-1].isHeader {
+	if p < l.offset {
+		l.offset = p
+		if l.offset > 0 && l.entries[l.view[l.offset-1]].isHeader {
 			l.offset--
 		}
 	}
-	if l.cursor >= l.offset+l.height {
-		l.offset = l.cursor - l.height + 1
+	if p >= l.offset+rows {
+		l.offset = p - rows + 1
 	}
 }
 
+// rows returns the number of entry rows, leaving a line for the filter when one is shown.
+func (l *ListPanel) rows() int {
+	if l.filtering || l.filter != "" {
+		return l.height - 1
+	}
+	return l.height
+}
+
 func (l *ListPanel) View() string {
 	if l.width <= 0 || l.height <= 0 {
 		return ""
 	}
 
 	var lines []string
-	end := l.offset + l.height
-	if end > len(l.entries) {
-		end = len(l.entries)
+	if l.filtering || l.filter != "" {
+		lines = append(lines, l.filterLine())
+	}
+	end := l.offset + l.rows()
+	if end > len(l.view) {
+		end = len(l.view)
 	}
 
-	for i := l.offset; i < end && len(lines) < l.height; i++ {
+	for p := l.offset; p < end && len(lines) < l.height; p++ {
+		i := l.view[p]
 		e := l.entries[i]
 		if e.isHeader {
 			label := "── " + e.header + " "
@@ -131,7 +256,7 @@ func (l *ListPanel) View() string {
 			}
 			lines = append(lines, groupHeaderStyle.Render(label))
 		} else {
-			lines = append(lines, l.renderItem(e.item, i == l.cursor))
+			lines = append(lines, l.renderItem(e.item, i == l.cursor, l.matches[i]))
 		}
 	}
 
@@ -142,13 +267,11 @@ func (l *ListPanel) View() string {
 	return strings.Join(lines, "\n")
 }
 
-func (l *ListPanel) renderItem(item ConfigItem, selected bool) string {
+// renderItem renders one field row, highlighting the runes of the name at
+// the matched positions.
+func (l *ListPanel) renderItem(item ConfigItem, selected bool, matched []int) string {
 	name := item.Field.Name
-	isSens := sensitive.IsSensitive(name)
-	var isToken bool
-	if s, ok := item.Value.(string); ok {
-		isToken = sensitive.LooksLikeToken(s)
-	}
+	isSens := isSensitiveItem(item)
 
 	nameWidth := 20
 	if l.width < 30 {
@@ -156,22 +279,37 @@ func (l *ListPanel) renderItem(item ConfigItem, selected bool) string {
 	}
 	if len(name) > nameWidth {
 		name = name[:nameWidth-1] + "…"
+		matched = slices.DeleteFunc(slices.Clone(matched), func(p int) bool { return p >= nameWidth-1 })
 	}
 	var val string
-	if isSens || isToken {
+	if isSens {
 		val = "🔒"
 	} else {
+		_, overridden := l.envOverrides[item.Field.Name]
+		atDefault := item.Value != nil && isDefaultValue(item.Field, item.Value)
 		valWidth := l.width - nameWidth - 4
+		if atDefault {
+			valWidth -= 11 // room for " (=default)"
+		}
 		if item.Modified {
 			valWidth -= 12 // room for " (not-saved)"
 		}
+		if overridden {
+			valWidth -= 6 // room for " (env)"
+		}
 		if valWidth < 3 {
 			valWidth = 3
 		}
 		val = formatValueCompact(item.Value, item.Field.Default, valWidth)
+		if atDefault {
+			val += " (=default)"
+		}
 		if item.Modified {
 			val += " (not-saved)"
 		}
+		if overridden {
+			val += " (env)"
+		}
 	}
 
 	line := fmt.Sprintf("%-*s %s", nameWidth, name, val)
@@ -179,13 +317,34 @@ func (l *ListPanel) renderItem(item ConfigItem, selected bool) string {
 		line = line[:l.width-3] + "…"
 	}
 
-	if selected {
-		return selectedItemStyle.Render("▶ " + line)
+	style, prefix := itemStyle, "  "
+	switch {
+	case selected:
+		style, prefix = selectedItemStyle, "▶ "
+	case isSens:
+		style = sensitiveItemStyle
+	}
+	if len(matched) == 0 {
+		return style.Render(prefix + line)
 	}
-	if isSens || isToken {
-		return sensitiveItemStyle.Render("  " + line)
+	return style.Render(prefix) + highlightRunes(line, matched, style)
+}
+
+// isDefaultValue reports whether a set value equals the field's documented
+// default. Lists and objects never do, since defaults are documented as text.
+func isDefaultValue(field copilot.SchemaField, value any) bool {
+	if field.Default == "" {
+		return false
+	}
+	switch v := value.(type) {
+	case string:
+		return v == field.Default
+	case bool:
+		return strconv.FormatBool(v) == field.Default
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64) == field.Default
 	}
-	return itemStyle.Render("  " + line)
+	return false
 }
 
 func formatValueCompact(val any, defaultVal string, maxLen int) string {
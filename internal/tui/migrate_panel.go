@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+)
+
+// MigratePanel lists the migrations that apply to the loaded config and asks
+// whether to apply them.
+type MigratePanel struct {
+	path   string
+	steps  []config.MigrationStep
+	width  int
+	height int
+}
+
+// NewMigratePanel creates the prompt for the steps planned for the file at path.
+func NewMigratePanel(path string, steps []config.MigrationStep) *MigratePanel {
+	return &MigratePanel{path: path, steps: steps}
+}
+
+// SetSize updates the panel content dimensions.
+func (p *MigratePanel) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+}
+
+// View renders the panel content.
+func (p *MigratePanel) View() string {
+	if p.width <= 0 || p.height <= 0 {
+		return ""
+	}
+
+	lines := []string{
+		detailHeaderStyle.Render(fmt.Sprintf("⬆ %d deprecated setting(s) can be migrated", len(p.steps))),
+		detailNoteStyle.Render(p.path),
+		"",
+	}
+	for _, s := range p.steps {
+		lines = append(lines, "  "+detailValueStyle.Render(formatValueCompact(s.String(), "", p.width-4)))
+	}
+	lines = append(lines,
+		"",
+		detailDescStyle.Render("Migrated rows are marked as not saved until you save."),
+		"",
+		detailNoteStyle.Render("enter migrate  •  esc skip"),
+	)
+	return strings.Join(lines, "\n")
+}
@@ -4,128 +4,140 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"reflect"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jsburckhardt/co-config/internal/config"
 	"github.com/jsburckhardt/co-config/internal/copilot"
-	"github.com/jsburckhardt/co-config/internal/sensitive"
+	"github.com/jsburckhardt/co-config/internal/profile"
+	"github.com/jsburckhardt/co-config/internal/validate"
 )
 
-// categoryExact maps exact field names to their TUI category.
-var categoryExact = map[string]string{
-	"model":            "Model & AI",
-	"reasoning_effort": "Model & AI",
-	"stream":           "Model & AI",
-	"experimental":     "Model & AI",
-
-	"theme":                 "Display",
-	"alt_screen":            "Display",
-	"render_markdown":       "Display",
-	"screen_reader":         "Display",
-	"banner":                "Display",
-	"beep":                  "Display",
-	"update_terminal_title": "Display",
-	"streamer_mode":         "Display",
-	"mouse":                 "Display",
-
-	"allowed_urls":    "URLs & Permissions",
-	"denied_urls":     "URLs & Permissions",
-	"trusted_folders": "URLs & Permissions",
-}
-
-// categoryPrefix maps field name prefixes to their TUI category.
-// Checked only when no exact match is found.
-var categoryPrefix = map[string]string{
-	"custom_agents.": "URLs & Permissions",
-	"ide.":           "IDE Integration",
-}
-
 // categoryOrder defines the display order of categories in the TUI.
-var categoryOrder = []string{
-	"Model & AI",
-	"Display",
-	"IDE Integration",
-	"URLs & Permissions",
-	"General",
-	"Sensitive",
-}
+var categoryOrder = append(append([]string{}, copilot.Categories...), unlistedCategory, "Sensitive")
+
+// unlistedCategory groups config keys that `copilot help config` does not list.
+const unlistedCategory = "Unknown / Unlisted"
 
 // fieldCategory returns the TUI category for a given field name.
 func fieldCategory(name string) string {
-	if cat, ok := categoryExact[name]; ok {
-		return cat
-	}
-	bestPrefix := ""
-	for prefix := range categoryPrefix {
-		if strings.HasPrefix(name, prefix) && len(prefix) > len(bestPrefix) {
-			bestPrefix = prefix
-		}
-	}
-	if bestPrefix != "" {
-		return categoryPrefix[bestPrefix]
-	}
-	return "General"
+	return copilot.FieldCategory(name)
 }
 
 // Model is the main Bubbletea model for the two-panel TUI.
 type Model struct {
-	cfg        *config.Config
-	schema     []copilot.SchemaField
-	envVars    []copilot.EnvVarInfo
-	version    string
-	configPath string
+	cfg     *config.Config
+	schema  []copilot.SchemaField
+	envVars []copilot.EnvVarInfo
+	version string
+	// envOverrides holds the fields overridden by set environment variables.
+	envOverrides map[string]copilot.EnvOverride
+	configPath   string
 
 	activeScope config.Scope
 	scopePaths  map[config.Scope]string
 	projectDir  string
+	profileDir  string
 
 	state            State
 	listPanel        *ListPanel
 	detailPanel      DetailPanel
 	envPanel         *EnvVarsPanel
+	effectivePanel   *EffectivePanel
+	backupPanel      *BackupPanel
+	conflictPanel    *ConflictPanel
+	reviewPanel      *ReviewPanel
+	unsavedPanel     *UnsavedPanel
+	profilePanel     *ProfilePanel
+	migratePanel     *MigratePanel
+	comparePanel     *ComparePanel
+	secretsPanel     *SecretsPanel
 	modelPickerPanel *ModelPickerPanel
 	keys             KeyMap
 
+	// diskConfig is the on-disk version loaded when a save detected an
+	// external change; conflictReturn is the state to resume afterwards.
+	diskConfig     *config.Config
+	conflictReturn State
+
+	history editHistory
+
+	// compareLayers holds the configs of the scopes other than the active one
+	// while comparing; compareDirty marks those that received copied values.
+	compareLayers map[config.Scope]*config.Config
+	compareDirty  map[config.Scope]bool
+
+	// reviewBeforeSave shows the pending changes for confirmation on save;
+	// reviewReturn is the state to resume when the review is cancelled.
+	reviewBeforeSave bool
+	reviewReturn     State
+
+	// secretsReturn is the state to resume when the sensitive values prompt closes.
+	secretsReturn State
+
+	// leaving is the quit or scope switch waiting on the unsaved-changes
+	// prompt (or on the save it started); exitReturn is the state to resume.
+	leaving    leaveAction
+	exitReturn State
+
+	// migrations are offered whenever a scope's file is loaded.
+	migrations []config.Migration
+
+	// schemaFrom and schemaChanges describe how the schema changed since the
+	// previously cached Copilot CLI version; shown as a header banner.
+	schemaFrom    string
+	schemaChanges copilot.SchemaDiff
+
+	// watchInterval enables polling of the scope files when non-zero;
+	// fileStamps holds their fingerprints from the previous poll.
+	watchInterval time.Duration
+	fileStamps    map[config.Scope]fileStamp
+
 	windowWidth  int
 	windowHeight int
 	err          error
 	saved        bool
+	reloaded     bool
+	// reloadConflicts names the keys whose unsaved edit overrode a newer
+	// on-disk value in the last reload.
+	reloadConflicts []string
 }
 
 // NewModel creates a new TUI model with two-panel layout.
 func NewModel(cfg *config.Config, schema []copilot.SchemaField, envVars []copilot.EnvVarInfo, version, configPath string, scope config.Scope, projectDir string) *Model {
 	entries := buildEntries(cfg, schema)
+	overrides := copilot.EnvOverrides(envVars, schema, os.LookupEnv)
 	lp := NewListPanel(entries)
+	lp.SetEnvOverrides(overrides)
 	dp := NewDetailPanel()
 	ep := NewEnvVarsPanel(envVars)
 
-	if item := lp.SelectedItem(); item != nil {
-		dp.SetField(item.Field, item.Value)
-	}
-
-	return &Model{
-		cfg:         cfg,
-		schema:      schema,
-		envVars:     envVars,
-		version:     version,
-		configPath:  configPath,
-		activeScope: scope,
-		projectDir:  projectDir,
-		scopePaths: map[config.Scope]string{
-			config.ScopeUser:         config.ScopePathFor(config.ScopeUser, projectDir),
-			config.ScopeProject:      config.ScopePathFor(config.ScopeProject, projectDir),
-			config.ScopeProjectLocal: config.ScopePathFor(config.ScopeProjectLocal, projectDir),
-		},
-		state:       StateBrowsing,
-		listPanel:   lp,
-		detailPanel: dp,
-		envPanel:    ep,
-		keys:        DefaultKeyMap(),
+	m := &Model{
+		cfg:          cfg,
+		schema:       schema,
+		envVars:      envVars,
+		version:      version,
+		envOverrides: overrides,
+		configPath:   configPath,
+		activeScope:  scope,
+		projectDir:   projectDir,
+		scopePaths:   config.ScopePaths(projectDir),
+		profileDir:   profile.DefaultDir(),
+		state:        StateBrowsing,
+		listPanel:    lp,
+		detailPanel:  dp,
+		envPanel:     ep,
+		keys:         DefaultKeyMap(),
 	}
+	m.syncDetailPanel()
+	return m
 }
 
 func buildEntries(cfg *config.Config, schema []copilot.SchemaField) []listEntry {
@@ -142,13 +154,7 @@ func buildEntries(cfg *config.Config, schema []copilot.SchemaField) []listEntry
 		value := cfg.Get(sf.Name)
 		item := ConfigItem{Field: sf, Value: value}
 
-		isSens := sensitive.IsSensitive(sf.Name)
-		var isToken bool
-		if s, ok := value.(string); ok {
-			isToken = sensitive.LooksLikeToken(s)
-		}
-
-		if isSens || isToken {
+		if isSensitiveItem(item) {
 			categories["Sensitive"] = append(categories["Sensitive"], item)
 		} else {
 			cat := fieldCategory(sf.Name)
@@ -156,6 +162,25 @@ func buildEntries(cfg *config.Config, schema []copilot.SchemaField) []listEntry
 		}
 	}
 
+	// Keys present in the config but missing from the schema (older keys,
+	// keys from newer CLI versions, internal state) get an inferred type.
+	var unlisted []string
+	for _, k := range cfg.Keys() {
+		if _, ok := copilot.FindField(schema, k); !ok {
+			unlisted = append(unlisted, k)
+		}
+	}
+	sort.Strings(unlisted)
+	for _, k := range unlisted {
+		value := cfg.Get(k)
+		item := ConfigItem{Field: unlistedField(k, value), Value: value, Unlisted: true}
+		if isSensitiveItem(item) {
+			categories["Sensitive"] = append(categories["Sensitive"], item)
+		} else {
+			categories[unlistedCategory] = append(categories[unlistedCategory], item)
+		}
+	}
+
 	var entries []listEntry
 	for _, cat := range categoryOrder {
 		items := categories[cat]
@@ -169,18 +194,120 @@ func buildEntries(cfg *config.Config, schema []copilot.SchemaField) []listEntry
 	return entries
 }
 
+// unlistedField synthesizes a schema field for a key the schema does not list.
+func unlistedField(name string, value any) copilot.SchemaField {
+	return copilot.SchemaField{
+		Name:        name,
+		Type:        inferFieldType(value),
+		Description: "This key is not listed by `copilot help config`. Its type was inferred from the stored JSON value.",
+	}
+}
+
+// inferFieldType maps a JSON value to the closest schema type. Values that
+// cannot be edited with a typed widget (numbers, objects, mixed lists) are "json".
+func inferFieldType(value any) string {
+	switch v := value.(type) {
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case []any:
+		for _, item := range v {
+			if _, ok := item.(string); !ok {
+				return "json"
+			}
+		}
+		return "list"
+	default:
+		return "json"
+	}
+}
+
+// nextEditType cycles the edit type used for an unlisted key.
+func nextEditType(t string) string {
+	switch t {
+	case "string":
+		return "bool"
+	case "bool":
+		return "list"
+	case "list":
+		return "json"
+	default:
+		return "string"
+	}
+}
+
 func isSensitiveItem(item ConfigItem) bool {
-	if sensitive.IsSensitive(item.Field.Name) {
-		return true
+	return isSensitiveValue(item.Field.Name, item.Value)
+}
+
+// SetReviewBeforeSave controls whether saving first shows the pending
+// changes against the on-disk file for confirmation.
+func (m *Model) SetReviewBeforeSave(review bool) {
+	m.reviewBeforeSave = review
+}
+
+// SetSchemaChanges shows a banner summarising how the schema changed since
+// Copilot CLI version from, including set keys that are now obsolete.
+func (m *Model) SetSchemaChanges(from string, diff copilot.SchemaDiff) {
+	m.schemaFrom = from
+	m.schemaChanges = diff
+}
+
+// schemaBanner summarises the schema changes, or returns "" when there are none.
+func (m *Model) schemaBanner() string {
+	d := m.schemaChanges
+	if d.Empty() {
+		return ""
 	}
-	if s, ok := item.Value.(string); ok && sensitive.LooksLikeToken(s) {
-		return true
+	banner := fmt.Sprintf("⬆ Since v%s: %d new, %d removed, %d changed settings (ccc schema diff)",
+		m.schemaFrom, len(d.Added), len(d.Removed), len(d.Changed))
+	if obsolete := d.Obsolete(m.cfg.Keys()); len(obsolete) > 0 {
+		banner += "  •  obsolete: " + strings.Join(obsolete, ", ")
 	}
-	return false
+	return banner
+}
+
+// SetMigrations sets the rules offered when a scope's file is loaded and
+// checks the file that is already loaded.
+func (m *Model) SetMigrations(rules []config.Migration) {
+	m.migrations = rules
+	m.checkMigrations()
+}
+
+// checkMigrations prompts to migrate the active scope's config when any rule applies.
+func (m *Model) checkMigrations() {
+	steps := config.PlanMigrations(m.cfg, m.migrations)
+	if len(steps) == 0 {
+		return
+	}
+	m.migratePanel = NewMigratePanel(m.configPath, steps)
+	m.state = StateMigrate
+	m.updateSizes()
+	slog.Info("migrations available", "path", m.configPath, "steps", len(steps))
+}
+
+// applyMigrations runs the migration rules on the in-memory config. Every
+// changed row is marked as not saved and each change can be undone.
+func (m *Model) applyMigrations() {
+	migrated := m.cfg.Clone()
+	steps := config.Migrate(migrated, m.migrations)
+	m.applyChanges(config.Diff(m.cfg, migrated))
+	slog.Info("migrations applied", "scope", m.activeScope.String(), "steps", len(steps))
+}
+
+// WatchFiles enables live reloading: every interval the three scope files
+// are checked and changes made by other processes are loaded into the TUI.
+func (m *Model) WatchFiles(interval time.Duration) {
+	m.watchInterval = interval
+	m.fileStamps = statScopes(m.scopePaths)
 }
 
 // Init initializes the model.
 func (m *Model) Init() tea.Cmd {
+	if m.watchInterval > 0 {
+		return watchFiles(m.watchInterval, m.scopePaths, m.fileStamps)
+	}
 	return nil
 }
 
@@ -194,6 +321,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
+	case watchTickMsg:
+		return m, watchFiles(m.watchInterval, m.scopePaths, m.fileStamps)
+	case configFileChangedMsg:
+		m.fileStamps = msg.stamps
+		m.handleFileChange(msg.scopes)
+		return m, watchFiles(m.watchInterval, m.scopePaths, m.fileStamps)
 	}
 	// Non-key messages (e.g. blink timers for text input)
 	if m.state == StateEditing {
@@ -202,6 +335,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.state == StateModelPicker && m.modelPickerPanel != nil {
 		return m, m.modelPickerPanel.Update(msg)
 	}
+	if m.state == StateFilter {
+		return m, m.listPanel.UpdateFilter(msg)
+	}
 	return m, nil
 }
 
@@ -220,13 +356,26 @@ func nextScope(s config.Scope) config.Scope {
 
 func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	k := msg.String()
+	m.reloaded = false
+	m.reloadConflicts = nil
 
 	// Global keys
 	if k == "ctrl+c" {
+		if m.state != StateExiting && m.hasUnsavedEdits() {
+			m.cancelPendingSave()
+			m.openUnsavedPrompt(leaveQuit)
+			return m, nil
+		}
 		slog.Info("user quit", "state", m.state)
 		return m, tea.Quit
 	}
 
+	if m.statePanelMissing() {
+		slog.Warn("state has no panel, returning to browsing", "state", m.state)
+		m.state = StateBrowsing
+		return m, nil
+	}
+
 	switch m.state {
 	case StateBrowsing:
 		switch k {
@@ -257,43 +406,103 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				slog.Info("editing", "field", item.Field.Name)
 				return m, m.detailPanel.StartEditing()
 			}
+		case "/":
+			m.state = StateFilter
+			return m, m.listPanel.StartFilter()
+		case "n":
+			m.listPanel.NextMatch()
+			m.syncDetailPanel()
+		case "N":
+			m.listPanel.PrevMatch()
+			m.syncDetailPanel()
+		case "esc":
+			if m.listPanel.Filter() != "" {
+				m.listPanel.SetFilter("")
+				m.syncDetailPanel()
+			}
 		case "right", "l", "tab":
 			m.state = StateEnvVars
 			slog.Info("switched to env vars view")
+		case "E":
+			m.openEffectiveView()
+		case "C":
+			m.openCompareView()
+		case "B":
+			m.openBackupView()
+		case "P":
+			m.openProfileView()
+		case "d":
+			if item := m.listPanel.SelectedItem(); item != nil && item.Value != nil && !isSensitiveItem(*item) {
+				m.deleteValue(*item)
+				m.syncDetailPanel()
+				m.saved = false
+				slog.Info("key unset", "field", item.Field.Name)
+			}
+		case "R":
+			m.resetCategory()
+		case "t":
+			if item := m.listPanel.SelectedItem(); item != nil && item.Unlisted && !isSensitiveItem(*item) {
+				field := item.Field
+				field.Type = nextEditType(field.Type)
+				m.listPanel.UpdateItemField(field)
+				m.syncDetailPanel()
+				slog.Info("unlisted key edit type changed", "field", field.Name, "type", field.Type)
+			}
+		case "u":
+			m.undo()
+		case "ctrl+r":
+			m.redo()
 		case "S":
-			m.activeScope = nextScope(m.activeScope)
-			m.configPath = m.scopePaths[m.activeScope]
-			cfg, err := config.LoadConfig(m.configPath)
-			if err != nil {
-				if errors.Is(err, config.ErrConfigNotFound) {
-					cfg = config.NewConfig()
-				} else {
-					m.err = err
-					return m, nil
-				}
+			if len(m.listPanel.ModifiedNames()) > 0 {
+				m.openUnsavedPrompt(leaveSwitchScope)
+				return m, nil
 			}
-			m.cfg = cfg
-			entries := buildEntries(m.cfg, m.schema)
-			m.listPanel = NewListPanel(entries)
-			m.listPanel.SetSize(m.listPanelWidth(), m.listPanelHeight())
+			m.switchScope()
+		}
+	case StateFilter:
+		switch k {
+		case "enter":
+			m.listPanel.StopFilter(true)
+			m.state = StateBrowsing
+			slog.Info("list filtered", "query", m.listPanel.Filter(), "matches", m.listPanel.MatchCount())
+		case "esc":
+			m.listPanel.StopFilter(false)
+			m.state = StateBrowsing
+		case "up", "ctrl+p":
+			m.listPanel.Up()
+		case "down", "ctrl+n":
+			m.listPanel.Down()
+		case "tab":
+			m.listPanel.NextMatch()
+		case "shift+tab":
+			m.listPanel.PrevMatch()
+		default:
+			cmd := m.listPanel.UpdateFilter(msg)
 			m.syncDetailPanel()
-			m.saved = false
-			m.err = nil
-			slog.Info("scope switched", "scope", m.activeScope.String(), "path", m.configPath)
+			return m, cmd
 		}
+		m.syncDetailPanel()
+		return m, nil
 	case StateEditing:
+		if m.detailPanel.Capturing() && k != "ctrl+s" {
+			return m, m.detailPanel.Update(msg)
+		}
 		switch k {
 		case "ctrl+s":
 			m.saveConfig()
 		case "esc":
+			if m.detailPanel.InvalidEdit() {
+				m.cancelEditing()
+				return m, nil
+			}
 			m.commitAndReturnToBrowsing()
 			return m, nil
 		case "enter":
-			if m.detailPanel.CurrentFieldType() != "list" {
+			if !isMultilineType(m.detailPanel.CurrentFieldType()) {
 				m.commitAndReturnToBrowsing()
 				return m, nil
 			}
-			// For list fields, fall through to detail panel
+			// For multi-line fields, fall through to detail panel
 			return m, m.detailPanel.Update(msg)
 		default:
 			// All other keys go to detail panel
@@ -310,8 +519,7 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "enter":
 			newValue := m.modelPickerPanel.SelectedValue()
 			if item := m.listPanel.SelectedItem(); item != nil {
-				m.cfg.Set(item.Field.Name, newValue)
-				m.listPanel.UpdateItemValue(item.Field.Name, newValue)
+				m.setValue(*item, newValue)
 				m.detailPanel.SetField(item.Field, newValue)
 				slog.Info("model picker confirmed", "field", item.Field.Name, "value", newValue)
 			}
@@ -321,8 +529,7 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "esc":
 			newValue := m.modelPickerPanel.SelectedValue()
 			if item := m.listPanel.SelectedItem(); item != nil {
-				m.cfg.Set(item.Field.Name, newValue)
-				m.listPanel.UpdateItemValue(item.Field.Name, newValue)
+				m.setValue(*item, newValue)
 				m.detailPanel.SetField(item.Field, newValue)
 				slog.Info("model picker confirmed via esc", "field", item.Field.Name, "value", newValue)
 			}
@@ -343,24 +550,791 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "down", "j":
 			m.envPanel.Down()
 		}
+	case StateEffective:
+		switch k {
+		case "esc", "E":
+			m.effectivePanel = nil
+			m.state = StateBrowsing
+			slog.Info("closed effective view")
+		case "up", "k":
+			m.effectivePanel.Up()
+		case "down", "j":
+			m.effectivePanel.Down()
+		}
+	case StateCompare:
+		switch k {
+		case "esc", "C":
+			if len(m.compareDirty) > 0 && m.comparePanel.message != compareUnsavedMessage {
+				m.comparePanel.SetMessage(compareUnsavedMessage)
+				return m, nil
+			}
+			m.closeCompareView()
+		case "ctrl+s":
+			m.saveCompare()
+		case "up", "k":
+			m.comparePanel.Up()
+		case "down", "j":
+			m.comparePanel.Down()
+		case "right", "l":
+			m.copyCompareValue(true)
+		case "left", "h":
+			m.copyCompareValue(false)
+		case ">", "shift+right":
+			m.moveCompareValue(true)
+		case "<", "shift+left":
+			m.moveCompareValue(false)
+		case "[":
+			m.comparePanel.CycleLeft()
+			m.refreshCompare()
+		case "]":
+			m.comparePanel.CycleRight()
+			m.refreshCompare()
+		}
+	case StateBackups:
+		switch k {
+		case "esc", "B":
+			m.backupPanel = nil
+			m.state = StateBrowsing
+			slog.Info("closed backup view")
+		case "up", "k":
+			m.backupPanel.Up()
+		case "down", "j":
+			m.backupPanel.Down()
+		case "enter":
+			if len(m.listPanel.ModifiedNames()) > 0 {
+				m.openUnsavedPrompt(leaveRestoreBackup)
+				return m, nil
+			}
+			m.restoreSelectedBackup()
+		}
+	case StateConflict:
+		switch k {
+		case "m", "t":
+			if m.conflictPanel.CanMerge() {
+				merged, conflicts := config.MergeWithDisk(m.cfg, m.diskConfig, k == "m")
+				slog.Info("merged with on-disk config", "conflicts", len(conflicts), "prefer_ours", k == "m")
+				disk := m.diskConfig
+				m.closeConflictView()
+				m.applyMerged(merged, disk)
+				m.confirmWrite()
+				return m, m.finishLeave()
+			}
+		case "o":
+			slog.Info("overwriting externally modified config", "path", m.configPath)
+			m.closeConflictView()
+			m.confirmWrite()
+			return m, m.finishLeave()
+		case "esc":
+			slog.Info("save cancelled after external modification")
+			m.closeConflictView()
+			m.leaving = leaveNone
+		}
+	case StateProfiles:
+		switch k {
+		case "esc", "P":
+			m.profilePanel = nil
+			m.state = StateBrowsing
+			slog.Info("closed profile view")
+		case "up", "k":
+			m.profilePanel.Up()
+		case "down", "j":
+			m.profilePanel.Down()
+		case "enter":
+			if p := m.profilePanel.Selected(); p != nil {
+				m.applyProfile(p)
+			}
+			m.profilePanel = nil
+			m.state = StateBrowsing
+		}
+	case StateExiting:
+		switch k {
+		case "s":
+			m.unsavedPanel = nil
+			if m.exitReturn == StateEditing {
+				// Commit the editor first; a value that does not pass
+				// validation keeps it open with the inline error.
+				m.state = StateEditing
+				m.commitAndReturnToBrowsing()
+				if m.state == StateEditing {
+					m.leaving = leaveNone
+					slog.Warn("edited value is not valid, staying in the editor", "error", m.detailPanel.validationErr)
+					return m, nil
+				}
+				m.exitReturn = StateBrowsing
+			}
+			m.state = StateSaving
+			if m.comparePanel != nil {
+				m.saveCompare()
+			} else {
+				m.saveConfig()
+			}
+			return m, m.finishLeave()
+		case "d":
+			action := m.leaving
+			m.closeUnsavedPrompt()
+			m.leaving = leaveNone
+			slog.Info("unsaved changes discarded", "action", action.String())
+			return m, m.performLeave(action)
+		case "esc":
+			m.closeUnsavedPrompt()
+			m.leaving = leaveNone
+		}
+	case StateMigrate:
+		switch k {
+		case "enter", "y":
+			m.applyMigrations()
+			m.migratePanel = nil
+			m.state = StateBrowsing
+		case "esc", "n":
+			slog.Info("migrations skipped", "path", m.configPath)
+			m.migratePanel = nil
+			m.state = StateBrowsing
+		}
+	case StateSecrets:
+		switch k {
+		case "enter", "y":
+			moved := m.moveSecretsToLocal()
+			m.closeSecretsView()
+			if !moved {
+				m.leaving = leaveNone
+				return m, nil
+			}
+			m.saveConfig()
+			return m, m.finishLeave()
+		case "esc", "n":
+			slog.Info("save cancelled, project settings hold sensitive values")
+			m.closeSecretsView()
+			m.leaving = leaveNone
+		}
+	case StateReview:
+		switch k {
+		case "enter", "y":
+			m.closeReviewView()
+			m.writeConfig()
+			return m, m.finishLeave()
+		case "esc", "n":
+			slog.Info("save cancelled after review")
+			m.closeReviewView()
+			m.leaving = leaveNone
+		case "up", "k":
+			m.reviewPanel.Up()
+		case "down", "j":
+			m.reviewPanel.Down()
+		}
 	}
 
 	return m, nil
 }
 
+// switchScope loads the next scope's file, discarding any unsaved edits.
+func (m *Model) switchScope() {
+	m.history.clear()
+	m.activeScope = nextScope(m.activeScope)
+	m.configPath = m.scopePaths[m.activeScope]
+	cfg, err := config.LoadConfig(m.configPath)
+	if err != nil {
+		if errors.Is(err, config.ErrConfigNotFound) {
+			cfg = config.NewConfig()
+		} else {
+			m.err = err
+			return
+		}
+	}
+	m.cfg = cfg
+	m.resetListPanel()
+	m.syncDetailPanel()
+	m.saved = false
+	m.err = nil
+	slog.Info("scope switched", "scope", m.activeScope.String(), "path", m.configPath)
+	m.checkMigrations()
+}
+
+// compareUnsavedMessage warns that closing the comparison discards copies
+// into scopes other than the active one.
+const compareUnsavedMessage = "Copied values are not saved: ctrl+s saves them, esc again discards them"
+
+// openCompareView compares the active scope with the next scope side by side,
+// starting on the selected field when either scope sets it.
+// The active scope uses the in-memory config so unsaved edits are included.
+func (m *Model) openCompareView() {
+	layers, err := config.LoadLayers(m.scopePaths)
+	if err != nil {
+		m.err = err
+		slog.Error("loading scopes for compare view failed", "error", err)
+		return
+	}
+	delete(layers, m.activeScope)
+	m.compareLayers = layers
+	m.compareDirty = make(map[config.Scope]bool)
+	m.comparePanel = NewComparePanel(m.activeScope, nextScope(m.activeScope), m.scopePaths)
+	m.refreshCompare()
+	if item := m.listPanel.SelectedItem(); item != nil {
+		m.comparePanel.Select(item.Field.Name)
+	}
+	m.updateSizes()
+	m.state = StateCompare
+	slog.Info("switched to compare view")
+}
+
+// closeCompareView leaves the comparison, discarding unsaved copies into
+// other scopes. Copies into the active scope stay as unsaved edits.
+func (m *Model) closeCompareView() {
+	m.comparePanel = nil
+	m.compareLayers = nil
+	m.compareDirty = nil
+	m.state = StateBrowsing
+	m.syncDetailPanel()
+	slog.Info("closed compare view")
+}
+
+// compareLayer returns the config compared for scope.
+func (m *Model) compareLayer(scope config.Scope) *config.Config {
+	if scope == m.activeScope {
+		return m.cfg
+	}
+	return m.compareLayers[scope]
+}
+
+func (m *Model) refreshCompare() {
+	left, right := m.comparePanel.Scopes()
+	m.comparePanel.SetLayers(map[config.Scope]*config.Config{left: m.compareLayer(left), right: m.compareLayer(right)})
+}
+
+// compareSides returns the selected row's scopes and values ordered from
+// source to target.
+func (m *Model) compareSides(row compareRow, toRight bool) (from, to config.Scope, value any, set bool, old any, oldSet bool) {
+	left, right := m.comparePanel.Scopes()
+	if toRight {
+		return left, right, row.left, row.leftSet, row.right, row.rightSet
+	}
+	return right, left, row.right, row.rightSet, row.left, row.leftSet
+}
+
+// copyCompareValue copies the selected key from one side of the comparison to
+// the other; copying an unset key unsets it. The active scope receives the
+// value as an unsaved, undoable edit. Sensitive values are never copied.
+func (m *Model) copyCompareValue(toRight bool) {
+	row, ok := m.comparePanel.Selected()
+	if !ok {
+		return
+	}
+	from, to, value, set, old, oldSet := m.compareSides(row, toRight)
+	if isSensitiveValue(row.key, value) || isSensitiveValue(row.key, old) {
+		m.comparePanel.SetMessage(row.key + " holds sensitive data and is not copied")
+		return
+	}
+	if !row.differs() {
+		return
+	}
+
+	m.setCompareValue(to, config.Change{Key: row.key, Old: old, OldSet: oldSet, New: value, NewSet: set})
+	m.comparePanel.SetMessage(fmt.Sprintf("Copied %s from %s to %s (not saved)", row.key, from.Label(), to.Label()))
+	slog.Info("value copied between scopes", "key", row.key, "from", from.String(), "to", to.String())
+	m.refreshCompare()
+}
+
+// moveCompareValue moves the selected key from one side of the comparison to
+// the other, unsetting it in the source scope. Sensitive values may move out
+// of shared project settings but never into them.
+func (m *Model) moveCompareValue(toRight bool) {
+	row, ok := m.comparePanel.Selected()
+	if !ok {
+		return
+	}
+	from, to, value, set, old, oldSet := m.compareSides(row, toRight)
+	if !set {
+		m.comparePanel.SetMessage(fmt.Sprintf("%s is not set in %s", row.key, from.Label()))
+		return
+	}
+	src, dst := m.compareLayer(from).Clone(), m.compareLayer(to).Clone()
+	if err := config.MoveValue(src, dst, row.key, to, false); err != nil {
+		m.comparePanel.SetMessage(err.Error())
+		return
+	}
+
+	m.setCompareValue(to, config.Change{Key: row.key, Old: old, OldSet: oldSet, New: value, NewSet: true})
+	m.setCompareValue(from, config.Change{Key: row.key, Old: value, OldSet: true})
+	m.comparePanel.SetMessage(fmt.Sprintf("Moved %s from %s to %s (not saved)", row.key, from.Label(), to.Label()))
+	slog.Info("value moved between scopes", "key", row.key, "from", from.String(), "to", to.String())
+	m.refreshCompare()
+}
+
+// setCompareValue applies c to scope. The active scope receives it as an
+// unsaved, undoable edit; other scopes hold it until saveCompare.
+func (m *Model) setCompareValue(scope config.Scope, c config.Change) {
+	if scope == m.activeScope {
+		m.applyChanges([]config.Change{c})
+		return
+	}
+	target := m.compareLayers[scope]
+	if c.NewSet {
+		target.Set(c.Key, c.New)
+	} else {
+		target.Delete(c.Key)
+	}
+	m.compareDirty[scope] = true
+}
+
+// saveCompare saves the active scope if it has unsaved edits, through the
+// usual review and conflict checks, and then the other scopes that received
+// copied or moved values. Nothing is written unless every other scope can be
+// written too, so a moved value never leaves one file without reaching the
+// other; cancelling the review also writes nothing.
+func (m *Model) saveCompare() {
+	if err := m.checkCompareScopes(); err != nil {
+		m.err = err
+		slog.Error("compared scope cannot be saved", "error", err)
+		return
+	}
+	if len(m.listPanel.ModifiedNames()) > 0 {
+		m.saveConfig()
+		return
+	}
+	m.err = nil
+	m.saveCompareScopes()
+}
+
+// checkCompareScopes reports why a scope other than the active one that
+// received copied or moved values cannot be written: its file changed on
+// disk since the comparison was opened, or it is shared and holds secrets.
+func (m *Model) checkCompareScopes() error {
+	for _, scope := range config.Precedence {
+		if !m.compareDirty[scope] {
+			continue
+		}
+		cfg, path := m.compareLayers[scope], m.scopePaths[scope]
+		changed, err := cfg.ChangedOnDisk(path)
+		if err != nil {
+			return err
+		}
+		if changed {
+			return fmt.Errorf("%s changed on disk since the comparison was opened; reopen it to copy again", path)
+		}
+		if err := config.CheckShared(scope, cfg); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// saveCompareScopes writes the scopes other than the active one that
+// received copied or moved values.
+func (m *Model) saveCompareScopes() {
+	if err := m.checkCompareScopes(); err != nil {
+		m.err = err
+		slog.Error("compared scope cannot be saved", "error", err)
+		return
+	}
+	for _, scope := range config.Precedence {
+		if !m.compareDirty[scope] {
+			continue
+		}
+		cfg, path := m.compareLayers[scope], m.scopePaths[scope]
+		if err := config.SaveScopeConfig(scope, path, cfg); err != nil {
+			m.err = err
+			slog.Error("saving compared scope failed", "scope", scope.String(), "error", err)
+			return
+		}
+		delete(m.compareDirty, scope)
+		slog.Info("compared scope saved", "scope", scope.String(), "path", path)
+	}
+	m.comparePanel.SetMessage("Saved")
+}
+
+// hasUnsavedEdits reports whether quitting now would lose edits: modified
+// rows in the active scope, a changed value still in the editor, or values
+// copied into other compared scopes.
+func (m *Model) hasUnsavedEdits() bool {
+	return len(m.unsavedKeys()) > 0 || len(m.compareDirty) > 0
+}
+
+// unsavedKeys lists the modified rows of the active scope and the field in
+// the editor when its value changed but is not committed yet.
+func (m *Model) unsavedKeys() []string {
+	keys := m.listPanel.ModifiedNames()
+	if m.state != StateEditing || !m.detailPanel.Changed() {
+		return keys
+	}
+	if item := m.listPanel.SelectedItem(); item != nil && !slices.Contains(keys, item.Field.Name) {
+		keys = append(keys, item.Field.Name)
+	}
+	return keys
+}
+
+// openUnsavedPrompt asks whether to save or discard unsaved edits before
+// performing action.
+func (m *Model) openUnsavedPrompt(action leaveAction) {
+	m.leaving = action
+	var copies []string
+	for _, scope := range config.Precedence {
+		if m.compareDirty[scope] {
+			copies = append(copies, m.scopePaths[scope])
+		}
+	}
+	m.unsavedPanel = NewUnsavedPanel(action, m.configPath, m.unsavedKeys(), copies)
+	m.exitReturn = m.state
+	m.state = StateExiting
+	m.updateSizes()
+	slog.Info("unsaved changes prompt", "action", action.String())
+}
+
+// closeUnsavedPrompt returns to the state the prompt interrupted. The
+// pending action stays set so a save started from the prompt can finish it.
+func (m *Model) closeUnsavedPrompt() {
+	m.unsavedPanel = nil
+	m.state = m.exitReturn
+}
+
+// resumeState returns the state to go back to when a view opened from s
+// closes. Views opened while saving from the unsaved-edits prompt go back to
+// the state the prompt interrupted.
+func (m *Model) resumeState(s State) State {
+	if s == StateSaving {
+		return m.exitReturn
+	}
+	return s
+}
+
+// cancelPendingSave closes the review, conflict or secrets view of a save in
+// progress, so a prompt opened next never returns to one of them.
+func (m *Model) cancelPendingSave() {
+	switch m.state {
+	case StateReview:
+		m.closeReviewView()
+	case StateConflict:
+		m.closeConflictView()
+	case StateSecrets:
+		m.closeSecretsView()
+	default:
+		return
+	}
+	m.leaving = leaveNone
+	slog.Info("pending save cancelled")
+}
+
+// statePanelMissing reports whether the current state renders a panel that
+// is not open, which would leave the key handlers without a panel.
+func (m *Model) statePanelMissing() bool {
+	switch m.state {
+	case StateEffective:
+		return m.effectivePanel == nil
+	case StateCompare:
+		return m.comparePanel == nil
+	case StateBackups:
+		return m.backupPanel == nil
+	case StateConflict:
+		return m.conflictPanel == nil
+	case StateReview:
+		return m.reviewPanel == nil
+	case StateSecrets:
+		return m.secretsPanel == nil
+	case StateProfiles:
+		return m.profilePanel == nil
+	case StateMigrate:
+		return m.migratePanel == nil
+	case StateExiting:
+		return m.unsavedPanel == nil
+	case StateSaving:
+		return true
+	}
+	return false
+}
+
+// finishLeave completes a pending quit, scope switch or restore once the save it
+// started has written everything. While a merge or review is still open, or
+// the save failed, the action stays pending or is dropped respectively. A save
+// that finished without opening a view resumes the state the prompt interrupted.
+func (m *Model) finishLeave() tea.Cmd {
+	if m.state == StateSaving {
+		m.state = m.exitReturn
+	}
+	if m.leaving == leaveNone || m.state == StateConflict || m.state == StateReview || m.state == StateSecrets {
+		return nil
+	}
+	action := m.leaving
+	m.leaving = leaveNone
+	if m.err != nil || m.hasUnsavedEdits() {
+		slog.Warn("save did not complete, staying", "action", action.String())
+		return nil
+	}
+	return m.performLeave(action)
+}
+
+// performLeave carries out action once unsaved edits are saved or discarded.
+func (m *Model) performLeave(action leaveAction) tea.Cmd {
+	switch action {
+	case leaveQuit:
+		slog.Info("user quit")
+		return tea.Quit
+	case leaveSwitchScope:
+		m.switchScope()
+	case leaveRestoreBackup:
+		m.restoreSelectedBackup()
+	}
+	return nil
+}
+
+// openEffectiveView merges all scopes and switches to the effective view.
+// The active scope uses the in-memory config so unsaved edits are reflected.
+func (m *Model) openEffectiveView() {
+	layers, err := config.LoadLayers(m.scopePaths)
+	if err != nil {
+		m.err = err
+		slog.Error("loading scopes for effective view failed", "error", err)
+		return
+	}
+	layers[m.activeScope] = m.cfg
+	m.effectivePanel = NewEffectivePanel(config.Merge(layers), m.scopePaths)
+	m.updateSizes()
+	m.state = StateEffective
+	slog.Info("switched to effective view")
+}
+
+// openBackupView lists the backups of the active scope's file.
+func (m *Model) openBackupView() {
+	backups, err := config.ListBackups(m.configPath)
+	if err != nil {
+		m.err = err
+		slog.Error("listing backups failed", "error", err)
+		return
+	}
+	m.backupPanel = NewBackupPanel(m.configPath, backups)
+	m.updateSizes()
+	m.state = StateBackups
+	slog.Info("switched to backup view", "count", len(backups))
+}
+
+// openProfileView lists the saved profiles.
+func (m *Model) openProfileView() {
+	profiles, err := profile.NewStore(m.profileDir).List()
+	if err != nil {
+		m.err = err
+		slog.Error("listing profiles failed", "error", err)
+		return
+	}
+	m.profilePanel = NewProfilePanel(m.profileDir, profiles)
+	m.updateSizes()
+	m.state = StateProfiles
+	slog.Info("switched to profile view", "count", len(profiles))
+}
+
+// applyProfile sets the profile's keys in the active scope's in-memory config
+// after checking them like `ccc profile apply` does. Every changed row is
+// marked as not saved and a single undo reverts the whole profile.
+func (m *Model) applyProfile(p *profile.Profile) {
+	if skipped := p.Bundle.SkipMissingFolders(); len(skipped) > 0 {
+		slog.Warn("skipped trusted folders missing on this machine", "name", p.Name, "folders", skipped)
+	}
+	if err := validate.Settings(m.schema, p.Bundle.Settings); err != nil {
+		m.err = fmt.Errorf("profile %s: %w", p.Name, err)
+		slog.Error("profile not applied", "name", p.Name, "error", err)
+		return
+	}
+	changes := p.Diff(m.cfg)
+	m.applyChangeSet(changes, true)
+	slog.Info("profile applied", "name", p.Name, "scope", m.activeScope.String(), "changes", len(changes))
+}
+
+// resetCategory unsets every key in the selected field's category, except
+// sensitive ones, as unsaved edits that a single undo reverts.
+func (m *Model) resetCategory() {
+	item := m.listPanel.SelectedItem()
+	if item == nil {
+		return
+	}
+	category, items := m.listPanel.GroupItems(item.Field.Name)
+	data := m.cfg.Data()
+	var changes []config.Change
+	for _, it := range items {
+		v, ok := data[it.Field.Name]
+		if !ok || isSensitiveItem(it) {
+			continue
+		}
+		changes = append(changes, config.Change{Key: it.Field.Name, Old: v, OldSet: true})
+	}
+	m.applyChangeSet(changes, true)
+	slog.Info("category reset to defaults", "category", category, "keys", len(changes))
+}
+
+// applyChanges applies changes to the in-memory config, marking every
+// changed row as not saved and recording each change for undo.
+func (m *Model) applyChanges(changes []config.Change) {
+	m.applyChangeSet(changes, false)
+}
+
+// applyChangeSet is applyChanges; with oneStep set the changes are recorded
+// as a single history step, so one undo reverts all of them.
+func (m *Model) applyChangeSet(changes []config.Change, oneStep bool) {
+	modified := m.listPanel.ModifiedNames()
+	var edits []fieldEdit
+	for _, c := range changes {
+		wasModified := false
+		for _, name := range modified {
+			if name == c.Key {
+				wasModified = true
+			}
+		}
+		e := fieldEdit{
+			key:         c.Key,
+			before:      c.Old,
+			beforeSet:   c.OldSet,
+			after:       c.New,
+			afterSet:    c.NewSet,
+			wasModified: wasModified,
+		}
+		if oneStep {
+			edits = append(edits, e)
+		} else {
+			m.history.record(e)
+		}
+		if c.NewSet {
+			m.cfg.Set(c.Key, c.New)
+		} else {
+			m.cfg.Delete(c.Key)
+		}
+		modified = append(modified, c.Key)
+	}
+	m.history.record(edits...)
+
+	// Rebuild so keys the schema does not list get a row too. Otherwise
+	// update the rows in place so removed keys keep their row until saved.
+	rebuild := false
+	for _, c := range changes {
+		if c.NewSet && !m.listPanel.HasItem(c.Key) {
+			rebuild = true
+		}
+	}
+	if rebuild {
+		m.rebuildEntries()
+	} else {
+		for _, c := range changes {
+			m.listPanel.UpdateItemValue(c.Key, m.cfg.Get(c.Key))
+		}
+	}
+	for _, name := range modified {
+		m.listPanel.SetItemModified(name, true)
+	}
+	m.syncDetailPanel()
+	if len(changes) > 0 {
+		m.saved = false
+	}
+	m.err = nil
+}
+
+// restoreSelectedBackup restores the highlighted backup and reloads the config.
+func (m *Model) restoreSelectedBackup() {
+	b := m.backupPanel.Selected()
+	if b == nil {
+		return
+	}
+	if err := config.RestoreScopeBackup(m.activeScope, m.configPath, b.ID); err != nil {
+		if errors.Is(err, config.ErrSensitiveShared) {
+			err = fmt.Errorf("%w; keep them in settings.local.json", err)
+		}
+		m.err = err
+		slog.Error("restore failed", "id", b.ID, "error", err)
+		return
+	}
+	slog.Info("backup restored", "id", b.ID, "path", m.configPath)
+	m.backupPanel = nil
+	m.state = StateBrowsing
+	m.saved = false
+	m.err = nil
+	m.history.clear()
+	if err := m.reloadFromDisk(); err != nil {
+		m.err = fmt.Errorf("restored but reload failed: %w", err)
+	}
+}
+
 func (m *Model) syncDetailPanel() {
 	if item := m.listPanel.SelectedItem(); item != nil {
 		m.detailPanel.SetField(item.Field, item.Value)
+		if o, ok := m.envOverrides[item.Field.Name]; ok {
+			m.detailPanel.SetEnvOverride(&o)
+		} else {
+			m.detailPanel.SetEnvOverride(nil)
+		}
+		m.detailPanel.ShowRawJSON(item.Unlisted)
+	}
+}
+
+// setValue sets a config key from the TUI, marking the row as not saved and
+// recording the change for undo.
+func (m *Model) setValue(item ConfigItem, value any) {
+	m.recordEdit(item, value, true)
+	m.cfg.Set(item.Field.Name, value)
+	m.listPanel.UpdateItemValue(item.Field.Name, value)
+}
+
+// deleteValue removes a config key from the TUI, recording the change for undo.
+func (m *Model) deleteValue(item ConfigItem) {
+	m.recordEdit(item, nil, false)
+	m.cfg.Delete(item.Field.Name)
+	m.listPanel.UpdateItemValue(item.Field.Name, nil)
+}
+
+func (m *Model) recordEdit(item ConfigItem, after any, afterSet bool) {
+	before, beforeSet := m.cfg.Data()[item.Field.Name]
+	m.history.record(fieldEdit{
+		key:         item.Field.Name,
+		before:      before,
+		beforeSet:   beforeSet,
+		after:       after,
+		afterSet:    afterSet,
+		wasModified: item.Modified,
+	})
+}
+
+// undo reverts the most recent step and restores the rows' modified markers.
+func (m *Model) undo() {
+	edits, ok := m.history.popUndo()
+	if !ok {
+		return
+	}
+	for i := len(edits) - 1; i >= 0; i-- {
+		e := edits[i]
+		m.applyHistoryValue(e.key, e.before, e.beforeSet)
+		m.listPanel.SetItemModified(e.key, e.wasModified)
+	}
+	slog.Info("edit undone", "field", edits[0].key, "fields", len(edits))
+}
+
+// redo re-applies the most recently undone step.
+func (m *Model) redo() {
+	edits, ok := m.history.popRedo()
+	if !ok {
+		return
+	}
+	for _, e := range edits {
+		m.applyHistoryValue(e.key, e.after, e.afterSet)
 	}
+	slog.Info("edit redone", "field", edits[0].key, "fields", len(edits))
+}
+
+// applyHistoryValue writes a value from the history to the config and list
+// and moves the cursor to the affected field.
+func (m *Model) applyHistoryValue(key string, value any, set bool) {
+	if set {
+		m.cfg.Set(key, value)
+	} else {
+		m.cfg.Delete(key)
+	}
+	m.listPanel.UpdateItemValue(key, value)
+	m.selectFieldByName(key)
+	m.syncDetailPanel()
+	m.saved = false
 }
 
 // commitAndReturnToBrowsing stops editing, commits the value, and returns to browsing.
+// If the edited value does not parse, editing continues and the error is shown inline.
 func (m *Model) commitAndReturnToBrowsing() {
+	if !m.detailPanel.Validate() {
+		return
+	}
 	newValue := m.detailPanel.StopEditing()
 	if item := m.listPanel.SelectedItem(); item != nil {
 		slog.Info("field updated", "field", item.Field.Name)
-		m.cfg.Set(item.Field.Name, newValue)
-		m.listPanel.UpdateItemValue(item.Field.Name, newValue)
+		m.setValue(*item, newValue)
 	}
 	m.saved = false
 	m.err = nil
@@ -368,10 +1342,173 @@ func (m *Model) commitAndReturnToBrowsing() {
 	m.syncDetailPanel()
 }
 
-// saveConfig persists config to disk, reloads to verify round-trip, and clears modified flags.
+// cancelEditing leaves the editor without committing, restoring the value
+// the field had when editing started.
+func (m *Model) cancelEditing() {
+	m.detailPanel.CancelEditing()
+	m.state = StateBrowsing
+	m.syncDetailPanel()
+	if item := m.listPanel.SelectedItem(); item != nil {
+		slog.Info("edit discarded", "field", item.Field.Name)
+	}
+}
+
+// saveConfig persists config to disk unless the file changed since it was
+// loaded, in which case the conflict view offers a merge instead.
 func (m *Model) saveConfig() {
+	changed, err := m.cfg.ChangedOnDisk(m.configPath)
+	if err != nil {
+		m.err = err
+		slog.Error("checking config file for external changes failed", "error", err)
+		return
+	}
+	if changed {
+		m.openConflictView()
+		return
+	}
+	m.confirmWrite()
+}
+
+// confirmWrite writes the config once it is cleared for writing: sensitive
+// values in project settings are offered a move to settings.local.json, and
+// with review enabled the changes against the file on disk are shown first.
+// Merges and overwrites from the conflict view go through here too, so the
+// content written is always the content reviewed.
+func (m *Model) confirmWrite() {
+	if config.CheckShared(m.activeScope, m.cfg) != nil {
+		m.openSecretsView()
+		return
+	}
+	if m.reviewBeforeSave && m.openReviewView() {
+		return
+	}
+	m.writeConfig()
+}
+
+// openSecretsView asks whether to move the sensitive values of the project
+// settings to settings.local.json instead of saving them.
+func (m *Model) openSecretsView() {
+	keys := config.SensitiveKeys(m.cfg)
+	m.secretsPanel = NewSecretsPanel(m.configPath, m.scopePaths[config.ScopeProjectLocal], keys)
+	m.secretsReturn = m.state
+	m.state = StateSecrets
+	m.updateSizes()
+	slog.Warn("project settings hold sensitive values", "keys", keys)
+}
+
+// closeSecretsView returns to the state that triggered the save.
+func (m *Model) closeSecretsView() {
+	m.secretsPanel = nil
+	m.state = m.resumeState(m.secretsReturn)
+}
+
+// moveSecretsToLocal writes the sensitive values of the project settings to
+// settings.local.json, then removes them from the project settings as
+// unsaved, undoable edits. The local file is written first so a value is
+// never dropped from both. It reports whether the values were moved.
+func (m *Model) moveSecretsToLocal() bool {
+	localPath := m.scopePaths[config.ScopeProjectLocal]
+	layers, err := config.LoadLayers(map[config.Scope]string{config.ScopeProjectLocal: localPath})
+	if err != nil {
+		m.err = err
+		slog.Error("loading project-local settings failed", "error", err)
+		return false
+	}
+	local := layers[config.ScopeProjectLocal]
+	var changes []config.Change
+	for _, key := range m.secretsPanel.Keys() {
+		value := m.cfg.Get(key)
+		if err := config.MoveValue(m.cfg, local, key, config.ScopeProjectLocal, true); err != nil {
+			m.err = err
+			return false
+		}
+		changes = append(changes, config.Change{Key: key, Old: value, OldSet: true})
+	}
+	if err := config.SaveConfig(localPath, local); err != nil {
+		m.err = err
+		slog.Error("moving sensitive values to project-local settings failed", "error", err)
+		return false
+	}
+	if m.compareLayers[config.ScopeProjectLocal] != nil && !m.compareDirty[config.ScopeProjectLocal] {
+		m.compareLayers[config.ScopeProjectLocal] = local
+	}
+	m.applyChanges(changes)
+	slog.Info("sensitive values moved to project-local settings", "keys", len(changes), "path", localPath)
+	return true
+}
+
+// openReviewView compares the in-memory config with the file on disk and
+// shows the differences. It reports false when there is nothing to review.
+func (m *Model) openReviewView() bool {
+	disk, err := config.LoadConfig(m.configPath)
+	if errors.Is(err, config.ErrConfigNotFound) {
+		disk, err = config.NewConfig(), nil
+	}
+	if err != nil {
+		// The file cannot be parsed; show the review against an empty config.
+		slog.Warn("loading on-disk config for review failed", "error", err)
+		disk = config.NewConfig()
+	}
+	changes := config.Diff(disk, m.cfg)
+	if len(changes) == 0 {
+		return false
+	}
+	m.reviewPanel = NewReviewPanel(m.configPath, changes)
+	m.reviewReturn = m.state
+	m.state = StateReview
+	m.updateSizes()
+	slog.Info("reviewing pending changes", "count", len(changes))
+	return true
+}
+
+// closeReviewView returns to the state that triggered the save.
+func (m *Model) closeReviewView() {
+	m.reviewPanel = nil
+	m.state = m.resumeState(m.reviewReturn)
+}
+
+// openConflictView loads the on-disk version and shows what a merge would do.
+func (m *Model) openConflictView() {
+	slog.Warn("config changed on disk since load", "path", m.configPath)
+	theirs, err := config.LoadConfig(m.configPath)
+	if errors.Is(err, config.ErrConfigNotFound) {
+		theirs, err = config.NewConfig(), nil
+	}
+
+	var changedOnDisk []string
+	var conflicts []config.Conflict
+	if err == nil {
+		m.diskConfig = theirs
+		_, conflicts = config.MergeWithDisk(m.cfg, theirs, true)
+		changedOnDisk = m.cfg.ExternalChanges(theirs)
+	}
+
+	m.conflictPanel = NewConflictPanel(m.configPath, m.cfg.ChangedKeys(), changedOnDisk, conflicts, err)
+	m.conflictReturn = m.state
+	m.state = StateConflict
+	m.updateSizes()
+}
+
+// closeConflictView returns to the state that triggered the save.
+func (m *Model) closeConflictView() {
+	m.conflictPanel = nil
+	m.diskConfig = nil
+	m.state = m.resumeState(m.conflictReturn)
+}
+
+// writeConfig writes config to disk, reloads to verify round-trip, and clears modified flags.
+func (m *Model) writeConfig() {
+	// Values moved out of the active scope must reach the compared scopes,
+	// so check those can be written before changing the active file.
+	if m.comparePanel != nil {
+		if err := m.checkCompareScopes(); err != nil {
+			m.err = err
+			slog.Error("compared scope cannot be saved", "error", err)
+			return
+		}
+	}
 	slog.Info("saving config", "path", m.configPath)
-	if err := config.SaveConfig(m.configPath, m.cfg); err != nil {
+	if err := config.SaveScopeConfig(m.activeScope, m.configPath, m.cfg); err != nil {
 		m.err = err
 		slog.Error("save failed", "error", err)
 		return
@@ -381,33 +1518,159 @@ func (m *Model) saveConfig() {
 	slog.Info("config saved")
 
 	// Post-save reload from disk
-	reloaded, err := config.LoadConfig(m.configPath)
-	if err != nil {
+	if err := m.reloadFromDisk(); err != nil {
 		m.err = fmt.Errorf("saved but reload failed: %w", err)
 		slog.Error("post-save reload failed", "error", err)
-	} else {
-		// Save cursor position by field name
-		var cursorFieldName string
-		if item := m.listPanel.SelectedItem(); item != nil {
-			cursorFieldName = item.Field.Name
-		}
+	}
+
+	// Clear modified flags
+	m.listPanel.ClearAllModified()
+	m.history.clear()
 
-		// Replace config and rebuild entries
-		m.cfg = reloaded
-		entries := buildEntries(m.cfg, m.schema)
-		m.listPanel = NewListPanel(entries)
-		m.listPanel.SetSize(m.listPanelWidth(), m.listPanelHeight())
+	if m.comparePanel != nil {
+		m.saveCompareScopes()
+		m.refreshCompare()
+	}
+}
 
-		// Restore cursor to same field name
-		if cursorFieldName != "" {
-			m.selectFieldByName(cursorFieldName)
-		}
+// reloadFromDisk replaces the in-memory config with the active scope's file
+// and rebuilds the list, keeping the cursor on the same field name.
+func (m *Model) reloadFromDisk() error {
+	reloaded, err := config.LoadConfig(m.configPath)
+	if err != nil {
+		return err
+	}
+	m.cfg = reloaded
+	m.rebuildEntries()
+	m.syncDetailPanel()
+	return nil
+}
+
+// resetListPanel replaces the list with one built from m.cfg, keeping the
+// environment override markers and the filter.
+func (m *Model) resetListPanel() {
+	filter := m.listPanel.Filter()
+	m.listPanel = NewListPanel(buildEntries(m.cfg, m.schema))
+	m.listPanel.SetEnvOverrides(m.envOverrides)
+	m.listPanel.SetSize(m.listPanelWidth(), m.listPanelHeight())
+	if filter != "" {
+		m.listPanel.SetFilter(filter)
+	}
+}
+
+// rebuildEntries rebuilds the list from m.cfg, keeping the cursor on the
+// same field name.
+func (m *Model) rebuildEntries() {
+	// Save cursor position by field name
+	var cursorFieldName string
+	if item := m.listPanel.SelectedItem(); item != nil {
+		cursorFieldName = item.Field.Name
+	}
+
+	m.resetListPanel()
+
+	// Restore cursor to same field name
+	if cursorFieldName != "" {
+		m.selectFieldByName(cursorFieldName)
+	}
+}
 
+// applyMerged replaces the in-memory config with merged, the result of
+// MergeWithDisk with disk, and rebuilds the list with the keys that still
+// differ from disk marked as not saved. The undo history of keys changed on
+// disk is rebased onto the disk value, or dropped where disk's value won, so
+// undo never brings back a value from before the reload.
+func (m *Model) applyMerged(merged, disk *config.Config) {
+	for _, key := range m.cfg.ExternalChanges(disk) {
+		diskValue, diskSet := disk.Data()[key]
+		value, set := merged.Data()[key]
+		if set == diskSet && reflect.DeepEqual(value, diskValue) {
+			m.history.drop(key)
+		} else {
+			m.history.rebase(key, diskValue, diskSet)
+		}
+	}
+	m.cfg = merged
+	m.rebuildEntries()
+	for _, key := range merged.ChangedKeys() {
+		m.listPanel.UpdateItemValue(key, merged.Get(key))
+	}
+	if m.state != StateEditing {
 		m.syncDetailPanel()
 	}
+}
 
-	// Clear modified flags
-	m.listPanel.ClearAllModified()
+// handleFileChange reacts to scope files changed by another process.
+// The active scope is merged into the TUI with unsaved edits taking
+// precedence; other scopes only affect the effective view.
+func (m *Model) handleFileChange(scopes []config.Scope) {
+	for _, scope := range scopes {
+		slog.Info("config file changed on disk", "scope", scope.String(), "path", m.scopePaths[scope])
+		if scope == m.activeScope {
+			m.reloadActiveScope()
+		}
+	}
+	if m.state == StateEffective {
+		m.openEffectiveView()
+	}
+	if m.state == StateCompare {
+		// Reload other scopes that changed on disk unless they hold unsaved copies.
+		for _, scope := range scopes {
+			if scope == m.activeScope || m.compareDirty[scope] {
+				continue
+			}
+			if cfg, err := config.LoadConfig(m.scopePaths[scope]); err == nil {
+				m.compareLayers[scope] = cfg
+			} else if errors.Is(err, config.ErrConfigNotFound) {
+				m.compareLayers[scope] = config.NewConfig()
+			}
+		}
+		m.refreshCompare()
+	}
+}
+
+// reloadActiveScope merges the on-disk version of the active scope into the
+// in-memory config. Keys edited locally keep their value and stay marked as
+// not saved. Writes made by ccc itself are recognised and ignored.
+func (m *Model) reloadActiveScope() {
+	changed, err := m.cfg.ChangedOnDisk(m.configPath)
+	if err != nil || !changed {
+		return
+	}
+	disk, err := config.LoadConfig(m.configPath)
+	if errors.Is(err, config.ErrConfigNotFound) {
+		disk, err = config.NewConfig(), nil
+	}
+	if err != nil {
+		// Possibly caught mid-write; the next change triggers another attempt.
+		slog.Warn("reloading externally modified config failed", "error", err)
+		return
+	}
+
+	if m.state == StateConflict {
+		// Refresh the pending merge against the newest on-disk version.
+		m.closeConflictView()
+		m.openConflictView()
+		return
+	}
+
+	merged, conflicts := config.MergeWithDisk(m.cfg, disk, true)
+	m.applyMerged(merged, disk)
+	if m.state == StateReview {
+		// Re-diff against the new on-disk version.
+		m.closeReviewView()
+		m.openReviewView()
+	}
+	m.saved = false
+	m.reloaded = true
+	m.reloadConflicts = nil
+	for _, c := range conflicts {
+		m.reloadConflicts = append(m.reloadConflicts, c.Key)
+	}
+	if len(conflicts) > 0 {
+		slog.Warn("unsaved edits override newer values on disk", "keys", m.reloadConflicts)
+	}
+	slog.Info("reloaded config changed on disk", "path", m.configPath, "unsaved_edits", len(merged.ChangedKeys()), "conflicts", len(conflicts))
 }
 
 // listPanelWidth returns the content width of the list panel.
@@ -437,13 +1700,7 @@ func (m *Model) listPanelHeight() int {
 
 // selectFieldByName moves the cursor to the entry with the given field name.
 func (m *Model) selectFieldByName(name string) {
-	for i, e := range m.listPanel.entries {
-		if !e.isHeader && e.item.Field.Name == name {
-			m.listPanel.cursor = i
-			m.listPanel.ensureVisible()
-			return
-		}
-	}
+	m.listPanel.Select(name)
 }
 
 func (m *Model) updateSizes() {
@@ -491,6 +1748,34 @@ func (m *Model) updateSizes() {
 	}
 	m.envPanel.SetSize(envPanelW, envPanelH)
 
+	if m.effectivePanel != nil {
+		m.effectivePanel.SetSize(envPanelW, envPanelH)
+	}
+	if m.comparePanel != nil {
+		m.comparePanel.SetSize(envPanelW, envPanelH)
+	}
+	if m.backupPanel != nil {
+		m.backupPanel.SetSize(envPanelW, envPanelH)
+	}
+	if m.conflictPanel != nil {
+		m.conflictPanel.SetSize(envPanelW, envPanelH)
+	}
+	if m.reviewPanel != nil {
+		m.reviewPanel.SetSize(envPanelW, envPanelH)
+	}
+	if m.unsavedPanel != nil {
+		m.unsavedPanel.SetSize(envPanelW, envPanelH)
+	}
+	if m.profilePanel != nil {
+		m.profilePanel.SetSize(envPanelW, envPanelH)
+	}
+	if m.migratePanel != nil {
+		m.migratePanel.SetSize(envPanelW, envPanelH)
+	}
+	if m.secretsPanel != nil {
+		m.secretsPanel.SetSize(envPanelW, envPanelH)
+	}
+
 	// Model picker sizing
 	if m.modelPickerPanel != nil {
 		pickerW := innerWidth - 4
@@ -533,10 +1818,22 @@ func (m *Model) View() string {
 	if m.saved {
 		version += "  " + savedStyle.Render("✓ Saved")
 	}
+	if m.reloaded {
+		version += "  " + savedStyle.Render("↻ Reloaded from disk")
+	}
+	if len(m.reloadConflicts) > 0 {
+		version += "  " + errorStyle.Render("! Unsaved edits override newer values on disk: "+strings.Join(m.reloadConflicts, ", "))
+	}
 	if m.err != nil {
 		version += "  " + errorStyle.Render("✗ "+m.err.Error())
 	}
-	titleBlock := lipgloss.JoinVertical(lipgloss.Left, title, version)
+	titleLines := []string{title, version}
+	if banner := m.schemaBanner(); banner != "" {
+		// Truncate instead of wrapping so the header keeps its height.
+		bannerWidth := innerWidth - 6 - lipgloss.Width(iconBlock)
+		titleLines = append(titleLines, errorStyle.MaxWidth(max(bannerWidth, 1)).Render(banner))
+	}
+	titleBlock := lipgloss.JoinVertical(lipgloss.Left, titleLines...)
 	headerContent := lipgloss.JoinHorizontal(lipgloss.Center, iconBlock, "  ", titleBlock)
 
 	// Panels
@@ -549,6 +1846,51 @@ func (m *Model) View() string {
 			Height(panelHeight - 2).
 			Render(envContent)
 		panels = envPanelRendered
+	case m.state == StateEffective && m.effectivePanel != nil:
+		panels = focusedPanelStyle.
+			Width(innerWidth - 4).
+			Height(panelHeight - 2).
+			Render(m.effectivePanel.View())
+	case m.state == StateCompare && m.comparePanel != nil:
+		panels = focusedPanelStyle.
+			Width(innerWidth - 4).
+			Height(panelHeight - 2).
+			Render(m.comparePanel.View())
+	case m.state == StateBackups && m.backupPanel != nil:
+		panels = focusedPanelStyle.
+			Width(innerWidth - 4).
+			Height(panelHeight - 2).
+			Render(m.backupPanel.View())
+	case m.state == StateConflict && m.conflictPanel != nil:
+		panels = focusedPanelStyle.
+			Width(innerWidth - 4).
+			Height(panelHeight - 2).
+			Render(m.conflictPanel.View())
+	case m.state == StateReview && m.reviewPanel != nil:
+		panels = focusedPanelStyle.
+			Width(innerWidth - 4).
+			Height(panelHeight - 2).
+			Render(m.reviewPanel.View())
+	case m.state == StateExiting && m.unsavedPanel != nil:
+		panels = focusedPanelStyle.
+			Width(innerWidth - 4).
+			Height(panelHeight - 2).
+			Render(m.unsavedPanel.View())
+	case m.state == StateProfiles && m.profilePanel != nil:
+		panels = focusedPanelStyle.
+			Width(innerWidth - 4).
+			Height(panelHeight - 2).
+			Render(m.profilePanel.View())
+	case m.state == StateMigrate && m.migratePanel != nil:
+		panels = focusedPanelStyle.
+			Width(innerWidth - 4).
+			Height(panelHeight - 2).
+			Render(m.migratePanel.View())
+	case m.state == StateSecrets && m.secretsPanel != nil:
+		panels = focusedPanelStyle.
+			Width(innerWidth - 4).
+			Height(panelHeight - 2).
+			Render(m.secretsPanel.View())
 	case m.state == StateModelPicker && m.modelPickerPanel != nil:
 		pickerContent := m.modelPickerPanel.View()
 		panels = focusedPanelStyle.
@@ -606,14 +1948,37 @@ func (m *Model) View() string {
 func (k KeyMap) ShortHelp(state State, fieldType string) []key.Binding {
 	switch state {
 	case StateBrowsing:
-		return []key.Binding{k.Up, k.Down, k.Enter, k.ScopeSwitch, k.Right, k.Tab, k.Save, k.Quit}
+		return []key.Binding{k.Up, k.Down, k.Enter, k.Filter, k.Unset, k.ResetCategory, k.ScopeSwitch, k.Effective, k.Compare, k.Backups, k.Profiles, k.Undo, k.Redo, k.Right, k.Tab, k.Save, k.Quit}
 	case StateEditing:
-		if fieldType != "list" {
+		if fieldType == "list" {
+			return []key.Binding{k.Up, k.Down, k.ListAdd, k.ListEdit, k.ListRemove, k.ListMove, k.ListSort, k.ListDedupe, k.Escape, k.Save, k.Quit}
+		}
+		if !isMultilineType(fieldType) {
 			return []key.Binding{k.Confirm, k.Escape, k.Save, k.Quit}
 		}
 		return []key.Binding{k.Escape, k.Save, k.Quit}
+	case StateFilter:
+		return []key.Binding{k.Up, k.Down, k.NextMatch, k.Confirm, k.Cancel, k.Quit}
 	case StateEnvVars:
 		return []key.Binding{k.Up, k.Down, k.Left, k.Tab, k.Quit}
+	case StateEffective:
+		return []key.Binding{k.Up, k.Down, k.Escape, k.Quit}
+	case StateBackups:
+		return []key.Binding{k.Up, k.Down, k.Restore, k.Escape, k.Quit}
+	case StateConflict:
+		return []key.Binding{k.Merge, k.MergeTheirs, k.Overwrite, k.Cancel, k.Quit}
+	case StateReview:
+		return []key.Binding{k.Up, k.Down, k.ConfirmSave, k.Cancel, k.Quit}
+	case StateCompare:
+		return []key.Binding{k.Up, k.Down, k.CompareCopy, k.CompareMove, k.CompareScopes, k.Save, k.Escape, k.Quit}
+	case StateProfiles:
+		return []key.Binding{k.Up, k.Down, k.Apply, k.Escape, k.Quit}
+	case StateMigrate:
+		return []key.Binding{k.Migrate, k.Skip, k.Quit}
+	case StateSecrets:
+		return []key.Binding{k.MoveSecrets, k.Cancel, k.Quit}
+	case StateExiting:
+		return []key.Binding{k.SaveFirst, k.Discard, k.Cancel, k.Quit}
 	case StateModelPicker:
 		return []key.Binding{k.Filter, k.Enter, k.Escape, k.Save, k.Quit}
 	default:
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jsburckhardt/co-config/internal/profile"
+)
+
+// ProfilePanel lists saved profiles so one can be applied to the active scope.
+type ProfilePanel struct {
+	dir      string
+	profiles []*profile.Profile
+	cursor   int
+	offset   int
+	width    int
+	height   int
+}
+
+// NewProfilePanel creates a profile picker for the profiles stored in dir.
+func NewProfilePanel(dir string, profiles []*profile.Profile) *ProfilePanel {
+	return &ProfilePanel{dir: dir, profiles: profiles}
+}
+
+// SetSize updates the panel content dimensions.
+func (p *ProfilePanel) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+	p.ensureVisible()
+}
+
+// Up moves cursor up one entry.
+func (p *ProfilePanel) Up() {
+	if p.cursor > 0 {
+		p.cursor--
+		p.ensureVisible()
+	}
+}
+
+// Down moves cursor down one entry.
+func (p *ProfilePanel) Down() {
+	if p.cursor < len(p.profiles)-1 {
+		p.cursor++
+		p.ensureVisible()
+	}
+}
+
+// Selected returns the highlighted profile, or nil if there are none.
+func (p *ProfilePanel) Selected() *profile.Profile {
+	if p.cursor >= 0 && p.cursor < len(p.profiles) {
+		return p.profiles[p.cursor]
+	}
+	return nil
+}
+
+// visibleRows returns how many profiles fit below the title lines.
+func (p *ProfilePanel) visibleRows() int {
+	visible := p.height - 3
+	if visible < 1 {
+		visible = 1
+	}
+	return visible
+}
+
+// ensureVisible adjusts offset so the cursor is within the visible viewport.
+func (p *ProfilePanel) ensureVisible() {
+	if p.height <= 0 || len(p.profiles) == 0 {
+		return
+	}
+	visible := p.visibleRows()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+visible {
+		p.offset = p.cursor - visible + 1
+	}
+}
+
+// View renders the panel content.
+func (p *ProfilePanel) View() string {
+	if p.width <= 0 || p.height <= 0 {
+		return ""
+	}
+
+	lines := []string{
+		detailHeaderStyle.Render("Apply profile"),
+		detailNoteStyle.Render(p.dir),
+		"",
+	}
+
+	if len(p.profiles) == 0 {
+		lines = append(lines, envVarDescStyle.Render("No profiles yet — create one with `ccc profile save <name>`"))
+	}
+
+	end := p.offset + p.visibleRows()
+	if end > len(p.profiles) {
+		end = len(p.profiles)
+	}
+	for i := p.offset; i < end; i++ {
+		pr := p.profiles[i]
+		keys := pr.Keys()
+		line := fmt.Sprintf("%-20s %s", pr.Name, formatValueCompact(strings.Join(keys, ", "), "", p.width-26))
+		if i == p.cursor {
+			lines = append(lines, selectedItemStyle.Render("▶ "+line))
+		} else {
+			lines = append(lines, itemStyle.Render("  "+line))
+		}
+	}
+
+	for len(lines) < p.height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jsburckhardt/co-config/internal/config"
+)
+
+// ReviewPanel lists the pending changes against the on-disk file so they can
+// be confirmed before saving.
+type ReviewPanel struct {
+	path    string
+	changes []config.Change
+	offset  int
+	width   int
+	height  int
+}
+
+// NewReviewPanel creates a review panel for the changes about to be written to path.
+func NewReviewPanel(path string, changes []config.Change) *ReviewPanel {
+	return &ReviewPanel{path: path, changes: changes}
+}
+
+// SetSize updates the panel content dimensions.
+func (p *ReviewPanel) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+	p.clampOffset()
+}
+
+// Up scrolls up one line.
+func (p *ReviewPanel) Up() {
+	if p.offset > 0 {
+		p.offset--
+	}
+}
+
+// Down scrolls down one line.
+func (p *ReviewPanel) Down() {
+	p.offset++
+	p.clampOffset()
+}
+
+// clampOffset keeps the scroll offset within the rendered body.
+func (p *ReviewPanel) clampOffset() {
+	maxOffset := len(p.bodyLines()) - p.bodyHeight()
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	if p.offset > maxOffset {
+		p.offset = maxOffset
+	}
+}
+
+// bodyHeight is the number of change lines that fit below the title block.
+func (p *ReviewPanel) bodyHeight() int {
+	h := p.height - 3
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// View renders the panel content.
+func (p *ReviewPanel) View() string {
+	if p.width <= 0 || p.height <= 0 {
+		return ""
+	}
+
+	lines := []string{
+		detailHeaderStyle.Render(fmt.Sprintf("Review %d pending change(s)", len(p.changes))),
+		detailNoteStyle.Render(p.path),
+		"",
+	}
+
+	body := p.bodyLines()
+	end := p.offset + p.bodyHeight()
+	if end > len(body) {
+		end = len(body)
+	}
+	lines = append(lines, body[p.offset:end]...)
+
+	for len(lines) < p.height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// bodyLines renders every change: old → new for scalars, +/- items for lists.
+func (p *ReviewPanel) bodyLines() []string {
+	var lines []string
+	valWidth := p.width - 12
+	for _, c := range p.changes {
+		lines = append(lines, "  "+envVarNameStyle.Render(c.Key))
+		switch {
+		case isSensitiveValue(c.Key, c.Old) || isSensitiveValue(c.Key, c.New):
+			lines = append(lines, "    "+sensitiveValueStyle.Render("🔒 redacted"))
+		case c.IsList():
+			for _, item := range c.Removed {
+				lines = append(lines, "    "+errorStyle.Render("- "+formatValueCompact(item, "", valWidth)))
+			}
+			for _, item := range c.Added {
+				lines = append(lines, "    "+savedStyle.Render("+ "+formatValueCompact(item, "", valWidth)))
+			}
+			if len(c.Added) == 0 && len(c.Removed) == 0 {
+				lines = append(lines, "    "+detailNoteStyle.Render("(items reordered)"))
+			}
+		default:
+			lines = append(lines, "    "+reviewValue(c.Old, c.OldSet, valWidth/2)+" → "+reviewValue(c.New, c.NewSet, valWidth/2))
+		}
+	}
+	return lines
+}
+
+// reviewValue renders one side of a scalar change.
+func reviewValue(value any, set bool, maxLen int) string {
+	if !set {
+		return detailNoteStyle.Render("(unset)")
+	}
+	return detailValueStyle.Render(formatValueCompact(value, "", maxLen))
+}
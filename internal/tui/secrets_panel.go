@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SecretsPanel explains that the project settings about to be saved hold
+// sensitive values and offers to move them to settings.local.json.
+type SecretsPanel struct {
+	path      string
+	localPath string
+	keys      []string
+	width     int
+	height    int
+}
+
+// NewSecretsPanel creates the prompt for the sensitive keys of the project
+// settings at path; localPath is the project-local settings file.
+func NewSecretsPanel(path, localPath string, keys []string) *SecretsPanel {
+	return &SecretsPanel{path: path, localPath: localPath, keys: keys}
+}
+
+// Keys returns the sensitive keys.
+func (p *SecretsPanel) Keys() []string {
+	return p.keys
+}
+
+// SetSize updates the panel content dimensions.
+func (p *SecretsPanel) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+}
+
+// View renders the panel content.
+func (p *SecretsPanel) View() string {
+	if p.width <= 0 || p.height <= 0 {
+		return ""
+	}
+
+	lines := []string{
+		errorStyle.Render(fmt.Sprintf("🔒 %d setting(s) hold sensitive data and cannot be saved to shared project settings", len(p.keys))),
+		detailNoteStyle.Render(p.path),
+		"",
+		detailDescStyle.Render("settings.json is committed with the project, so tokens saved there would be shared with everyone who can read the repository."),
+		"",
+	}
+	for _, key := range p.keys {
+		lines = append(lines, "  "+sensitiveValueStyle.Render("🔒 "+key))
+	}
+	lines = append(lines,
+		"",
+		detailDescStyle.Render("Move them to "+p.localPath+", which stays on this machine, and save the rest?"),
+		"",
+		detailNoteStyle.Render("enter move to settings.local.json  •  esc cancel save"),
+	)
+	return strings.Join(lines, "\n")
+}
@@ -10,12 +10,31 @@ const (
 	StateEditing
 	// StateModelPicker: full-screen filterable list overlay for large enum fields
 	StateModelPicker
-	// StateSaving: persisting changes to config file
+	// StateSaving: saving from the unsaved-edits prompt; the review, conflict and
+	// secrets views it opens return to the state the prompt interrupted
 	StateSaving
-	// StateExiting: final save (if needed) and quit
+	// StateExiting: unsaved edits prompt before quitting, switching scope or restoring; save, discard or cancel
 	StateExiting
 	// StateEnvVars: environment variables view is active (read-only)
 	StateEnvVars
+	// StateEffective: merged view of all scopes is active (read-only)
+	StateEffective
+	// StateBackups: backup list for the active scope's file, Enter restores
+	StateBackups
+	// StateConflict: the file changed on disk before saving; merge, overwrite or cancel
+	StateConflict
+	// StateReview: pending changes against the on-disk file, confirm to save
+	StateReview
+	// StateProfiles: saved profiles, Enter applies the selected one to the active scope
+	StateProfiles
+	// StateMigrate: the loaded config has deprecated keys; migrate or skip
+	StateMigrate
+	// StateFilter: typing a query that filters the config list
+	StateFilter
+	// StateCompare: two scopes side by side; values can be copied between them
+	StateCompare
+	// StateSecrets: project settings about to be saved hold sensitive values; move them to local or cancel
+	StateSecrets
 )
 
 func (s State) String() string {
@@ -32,6 +51,24 @@ func (s State) String() string {
 		return "Exiting"
 	case StateEnvVars:
 		return "EnvVars"
+	case StateEffective:
+		return "Effective"
+	case StateBackups:
+		return "Backups"
+	case StateConflict:
+		return "Conflict"
+	case StateReview:
+		return "Review"
+	case StateProfiles:
+		return "Profiles"
+	case StateMigrate:
+		return "Migrate"
+	case StateFilter:
+		return "Filter"
+	case StateCompare:
+		return "Compare"
+	case StateSecrets:
+		return "Secrets"
 	default:
 		return "Unknown"
 	}
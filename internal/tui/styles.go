@@ -120,6 +120,10 @@ var (
 
 	envVarDescStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.AdaptiveColor{Light: "#2D3748", Dark: "#E2E8F0"})
+
+	diffValueStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "#C05621", Dark: "#FB923C"}).
+			Bold(true)
 )
 
 const copilotIcon = "╭─╮╭─╮\n╰─╯╰─╯\n█ ▘▝ █\n ▔▔▔▔ "
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"strings"
+)
+
+// leaveAction is what the user was doing when unsaved edits interrupted them.
+type leaveAction int
+
+const (
+	leaveNone leaveAction = iota
+	leaveQuit
+	leaveSwitchScope
+	leaveRestoreBackup
+)
+
+// String returns the action as shown in the unsaved-changes prompt.
+func (a leaveAction) String() string {
+	switch a {
+	case leaveQuit:
+		return "quitting"
+	case leaveSwitchScope:
+		return "switching scope"
+	case leaveRestoreBackup:
+		return "restoring the backup"
+	default:
+		return "leaving"
+	}
+}
+
+// UnsavedPanel asks whether to save or discard unsaved edits before
+// quitting, switching scope or restoring a backup.
+type UnsavedPanel struct {
+	action leaveAction
+	path   string
+	keys   []string
+	copies []string
+	width  int
+	height int
+}
+
+// NewUnsavedPanel creates the prompt for the given action; keys are the
+// fields with unsaved edits in the file at path, and copies the other files
+// that received values copied in the compare view.
+func NewUnsavedPanel(action leaveAction, path string, keys, copies []string) *UnsavedPanel {
+	return &UnsavedPanel{action: action, path: path, keys: keys, copies: copies}
+}
+
+// SetSize updates the panel content dimensions.
+func (p *UnsavedPanel) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+}
+
+// View renders the panel content.
+func (p *UnsavedPanel) View() string {
+	if p.width <= 0 || p.height <= 0 {
+		return ""
+	}
+
+	lines := []string{
+		errorStyle.Render("⚠ You have unsaved changes"),
+		detailNoteStyle.Render(p.path),
+		"",
+		detailLabelStyle.Render("Not saved: ") + keyList(p.keys),
+	}
+	if len(p.copies) > 0 {
+		lines = append(lines, detailLabelStyle.Render("Copied into: ")+strings.Join(p.copies, ", "))
+	}
+	lines = append(lines,
+		"",
+		detailDescStyle.Render("Save them before "+p.action.String()+"?"),
+		"",
+		detailNoteStyle.Render("s save  •  d discard  •  esc cancel"),
+	)
+	return strings.Join(lines, "\n")
+}
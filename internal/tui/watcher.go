@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jsburckhardt/co-config/internal/config"
+)
+
+// DefaultWatchInterval is how often the scope files are polled for changes.
+const DefaultWatchInterval = time.Second
+
+// fileStamp is the cheap stat-based fingerprint the watcher compares.
+type fileStamp struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// statFile fingerprints path; a missing or unreadable file reports exists=false.
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{exists: true, modTime: info.ModTime(), size: info.Size()}
+}
+
+// statScopes fingerprints every scope file.
+func statScopes(paths map[config.Scope]string) map[config.Scope]fileStamp {
+	stamps := make(map[config.Scope]fileStamp, len(paths))
+	for scope, path := range paths {
+		stamps[scope] = statFile(path)
+	}
+	return stamps
+}
+
+// watchTickMsg is delivered when a poll found no changes.
+type watchTickMsg struct{}
+
+// configFileChangedMsg is delivered when one or more scope files changed
+// on disk since the previous poll.
+type configFileChangedMsg struct {
+	scopes []config.Scope
+	stamps map[config.Scope]fileStamp
+}
+
+// watchFiles polls the scope files once after the interval. The previous
+// fingerprints are passed by value so the poll never touches model state.
+func watchFiles(interval time.Duration, paths map[config.Scope]string, prev map[config.Scope]fileStamp) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		stamps := statScopes(paths)
+		var changed []config.Scope
+		for _, scope := range config.Precedence {
+			if stamps[scope] != prev[scope] {
+				changed = append(changed, scope)
+			}
+		}
+		if len(changed) == 0 {
+			return watchTickMsg{}
+		}
+		return configFileChangedMsg{scopes: changed, stamps: stamps}
+	})
+}
@@ -0,0 +1,263 @@
+// Package validate checks config values against the detected schema before
+// they are written, so the TUI and the command-line paths reject the same
+// malformed input with the same messages.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jsburckhardt/co-config/internal/copilot"
+)
+
+// ErrPathNotExist is returned by ExistingAbsPath for paths that do not exist.
+var ErrPathNotExist = errors.New("does not exist")
+
+// itemRules maps list field names to the check applied to each string item.
+var itemRules = map[string]func(string) error{
+	"allowed_urls":    URLPattern,
+	"denied_urls":     URLPattern,
+	"trusted_folders": ExistingAbsPath,
+}
+
+// Value checks value for the given schema field. A nil value (unset) is
+// always valid. List items that are not strings are left unchecked so values
+// ccc cannot edit are never rejected.
+func Value(field copilot.SchemaField, value any) error {
+	if value == nil {
+		return nil
+	}
+	switch field.Type {
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s expects a boolean", field.Name)
+		}
+	case "enum":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s expects one of its listed options", field.Name)
+		}
+		if len(field.Options) > 0 && !slices.Contains(field.Options, s) {
+			return fmt.Errorf("%s must be one of %s, got %q", field.Name, strings.Join(field.Options, ", "), s)
+		}
+	case "list":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s expects a list", field.Name)
+		}
+		return List(field.Name, items)
+	}
+	return nil
+}
+
+// Settings checks every value of settings that has a schema field and
+// reports all invalid keys at once.
+func Settings(schema []copilot.SchemaField, settings map[string]any) error {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if field, ok := copilot.FindField(schema, key); ok {
+			if err := Value(field, settings[key]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", copilot.ErrInvalidValue, errors.Join(errs...))
+	}
+	return nil
+}
+
+// Change checks value like Value, but a list edited from before is only
+// checked for the items it adds, so entries that were already stored, such
+// as a trusted folder removed since, never keep the edit from being committed.
+func Change(field copilot.SchemaField, before, value any) error {
+	items, ok := value.([]any)
+	if field.Type != "list" || !ok {
+		return Value(field, value)
+	}
+	old, _ := before.([]any)
+	return listChanges(field.Name, old, items)
+}
+
+// List checks a list value: duplicate items are rejected, and string items
+// are checked with the rule registered for the field name, if any.
+func List(name string, items []any) error {
+	return listChanges(name, nil, items)
+}
+
+// listChanges checks the items of items that are not in before, counting
+// repeated items, as List does.
+func listChanges(name string, before, items []any) error {
+	existing := slices.Clone(before)
+	for i, item := range items {
+		if k := slices.IndexFunc(existing, func(e any) bool { return reflect.DeepEqual(e, item) }); k >= 0 {
+			existing = slices.Delete(existing, k, k+1)
+			continue
+		}
+		for j := 0; j < i; j++ {
+			if reflect.DeepEqual(items[j], item) {
+				return fmt.Errorf("%s: item %d duplicates item %d (%v)", name, i+1, j+1, item)
+			}
+		}
+		s, ok := item.(string)
+		if !ok {
+			continue
+		}
+		if err := Item(name, s); err != nil {
+			return fmt.Errorf("%s: item %d: %w", name, i+1, err)
+		}
+	}
+	return nil
+}
+
+// Item checks one string item of the named list field with the rule
+// registered for it. Fields without a rule accept any item.
+func Item(name, s string) error {
+	if rule := itemRules[name]; rule != nil {
+		return rule(s)
+	}
+	return nil
+}
+
+// URLPattern accepts the URL forms used by allowed_urls and denied_urls:
+// a full http(s) URL, a bare domain with optional port and path, or a domain
+// with a leading "*." wildcard (e.g. "https://github.com", "api.github.com",
+// "*.example.com/docs").
+func URLPattern(s string) error {
+	if s == "" {
+		return fmt.Errorf("empty URL")
+	}
+	if strings.ContainsAny(s, " \t\n") {
+		return fmt.Errorf("%q contains whitespace", s)
+	}
+
+	hostport := s
+	if i := strings.Index(s, "://"); i >= 0 {
+		u, err := url.Parse(s)
+		if err != nil {
+			return fmt.Errorf("%q is not a valid URL: %w", s, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("%q: scheme must be http or https", s)
+		}
+		hostport = u.Host
+	} else if i := strings.IndexAny(s, "/?#"); i >= 0 {
+		hostport = s[:i]
+	}
+
+	host := hostport
+	if h, port, err := net.SplitHostPort(hostport); err == nil {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("%q has an invalid port", s)
+		}
+		host = h
+	}
+	if err := hostPattern(host); err != nil {
+		return fmt.Errorf("%q is not a valid URL, domain or wildcard pattern: %w", s, err)
+	}
+	return nil
+}
+
+// hostPattern accepts an IP address, a host name, or "*." followed by a host name.
+func hostPattern(host string) error {
+	if host == "" {
+		return fmt.Errorf("missing host")
+	}
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	host = strings.TrimPrefix(host, "*.")
+	if strings.Contains(host, "*") {
+		return fmt.Errorf("wildcards are only allowed as a leading \"*.\"")
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("invalid host label %q", label)
+		}
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("host label %q cannot start or end with '-'", label)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return fmt.Errorf("invalid character %q in host", r)
+			}
+		}
+	}
+	return nil
+}
+
+// URLConflicts reports allowed_urls entries that denied_urls also matches:
+// the same host, or a host covered by a denied "*." wildcard. Non-string
+// items are ignored.
+func URLConflicts(allowed, denied []any) []string {
+	var conflicts []string
+	for _, a := range allowed {
+		as, ok := a.(string)
+		if !ok {
+			continue
+		}
+		ah := patternHost(as)
+		for _, d := range denied {
+			ds, ok := d.(string)
+			if !ok {
+				continue
+			}
+			dh := patternHost(ds)
+			switch {
+			case ah == dh:
+				conflicts = append(conflicts, fmt.Sprintf("%q is both allowed and denied (%q)", as, ds))
+			case strings.HasPrefix(dh, "*.") && strings.HasSuffix(strings.TrimPrefix(ah, "*."), dh[1:]):
+				conflicts = append(conflicts, fmt.Sprintf("allowed %q is covered by denied %q", as, ds))
+			}
+		}
+	}
+	return conflicts
+}
+
+// patternHost returns the lower-cased host of a URL pattern, keeping a
+// leading "*." wildcard and dropping the scheme, port and path.
+func patternHost(s string) string {
+	hostport := s
+	if i := strings.Index(s, "://"); i >= 0 {
+		hostport = s[i+3:]
+	}
+	if i := strings.IndexAny(hostport, "/?#"); i >= 0 {
+		hostport = hostport[:i]
+	}
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		hostport = h
+	}
+	return strings.ToLower(hostport)
+}
+
+// ExistingAbsPath accepts absolute paths of directories that exist.
+func ExistingAbsPath(s string) error {
+	if !filepath.IsAbs(s) {
+		return fmt.Errorf("%q is not an absolute path", s)
+	}
+	info, err := os.Stat(s)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%q %w", s, ErrPathNotExist)
+		}
+		return fmt.Errorf("%q: %w", s, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%q is not a directory", s)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package validate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jsburckhardt/co-config/internal/copilot"
+)
+
+// UT-VAL-001: URLPattern accepts URLs, domains, ports, paths and leading wildcards
+func TestURLPattern_Valid(t *testing.T) {
+	valid := []string{
+		"https://github.com",
+		"http://localhost:8080/api",
+		"github.com",
+		"api.github.com/repos",
+		"*.example.com",
+		"https://*.example.com/docs",
+		"127.0.0.1:3000",
+	}
+	for _, s := range valid {
+		if err := URLPattern(s); err != nil {
+			t.Errorf("URLPattern(%q) = %v, want nil", s, err)
+		}
+	}
+}
+
+// UT-VAL-002: URLPattern rejects malformed entries
+func TestURLPattern_Invalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"not a url",
+		"ftp://example.com",
+		"exa*mple.com",
+		"*",
+		"example..com",
+		"-bad.com",
+		"example.com:99999",
+		"https://",
+	}
+	for _, s := range invalid {
+		if err := URLPattern(s); err == nil {
+			t.Errorf("URLPattern(%q) = nil, want error", s)
+		}
+	}
+}
+
+// UT-VAL-003: ExistingAbsPath requires an absolute path to an existing directory
+func TestExistingAbsPath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(file, []byte("x"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := ExistingAbsPath(dir); err != nil {
+		t.Errorf("ExistingAbsPath(%q) = %v, want nil", dir, err)
+	}
+	for _, s := range []string{"relative/dir", filepath.Join(dir, "missing"), file} {
+		if err := ExistingAbsPath(s); err == nil {
+			t.Errorf("ExistingAbsPath(%q) = nil, want error", s)
+		}
+	}
+}
+
+// UT-VAL-004: Value checks enum membership and basic types
+func TestValue_Types(t *testing.T) {
+	enum := copilot.SchemaField{Name: "theme", Type: "enum", Options: []string{"dark", "light"}}
+	if err := Value(enum, "dark"); err != nil {
+		t.Errorf("valid enum rejected: %v", err)
+	}
+	if err := Value(enum, "blue"); err == nil {
+		t.Error("enum value outside options should be rejected")
+	}
+	if err := Value(copilot.SchemaField{Name: "beep", Type: "bool"}, "yes"); err == nil {
+		t.Error("non-bool value for bool field should be rejected")
+	}
+	if err := Value(copilot.SchemaField{Name: "allowed_urls", Type: "list"}, "github.com"); err == nil {
+		t.Error("non-list value for list field should be rejected")
+	}
+	if err := Value(enum, nil); err != nil {
+		t.Errorf("unset value should be valid, got %v", err)
+	}
+}
+
+// UT-VAL-005: Value checks list items by field name and rejects duplicates
+func TestValue_Lists(t *testing.T) {
+	urls := copilot.SchemaField{Name: "allowed_urls", Type: "list"}
+	if err := Value(urls, []any{"github.com", "*.example.com", 42.0}); err != nil {
+		t.Errorf("valid URL list rejected: %v", err)
+	}
+	if err := Value(urls, []any{"github.com", "bad url"}); err == nil {
+		t.Error("malformed URL item should be rejected")
+	}
+	if err := Value(urls, []any{"github.com", "github.com"}); err == nil {
+		t.Error("duplicate items should be rejected")
+	}
+
+	folders := copilot.SchemaField{Name: "trusted_folders", Type: "list"}
+	if err := Value(folders, []any{t.TempDir()}); err != nil {
+		t.Errorf("existing folder rejected: %v", err)
+	}
+	if err := Value(folders, []any{"relative"}); err == nil {
+		t.Error("relative folder should be rejected")
+	}
+
+	// Lists without item rules only check duplicates
+	messages := copilot.SchemaField{Name: "launch_messages", Type: "list"}
+	if err := Value(messages, []any{"hello world", "anything goes"}); err != nil {
+		t.Errorf("free-form list rejected: %v", err)
+	}
+}
+
+// UT-VAL-006: URLConflicts finds allowed entries that denied entries match
+func TestURLConflicts(t *testing.T) {
+	allowed := []any{"https://GitHub.com/docs", "api.example.com", "*.api.example.com", "example.org", 42}
+	denied := []any{"github.com:443", "*.example.com", "other.org"}
+
+	got := URLConflicts(allowed, denied)
+	want := []string{
+		`"https://GitHub.com/docs" is both allowed and denied ("github.com:443")`,
+		`allowed "api.example.com" is covered by denied "*.example.com"`,
+		`allowed "*.api.example.com" is covered by denied "*.example.com"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("URLConflicts = %q, want %q", got, want)
+	}
+	if len(URLConflicts([]any{"example.com"}, []any{"*.example.com"})) != 0 {
+		t.Error("a wildcard should only cover subdomains")
+	}
+}
+
+// UT-VAL-007: Change only checks the list items an edit adds
+func TestChange_OnlyAddedItems(t *testing.T) {
+	folders := copilot.SchemaField{Name: "trusted_folders", Type: "list"}
+	gone := filepath.Join(t.TempDir(), "gone")
+	stored := []any{gone, gone}
+	if err := Change(folders, stored, []any{gone, gone}); err != nil {
+		t.Errorf("stored items must not be rechecked: %v", err)
+	}
+	if err := Change(folders, stored, []any{gone, gone, t.TempDir()}); err != nil {
+		t.Errorf("adding a valid folder rejected: %v", err)
+	}
+	if err := Change(folders, stored, []any{gone, gone, gone}); err == nil {
+		t.Error("a third copy of a stored item is new and should be rejected")
+	}
+	if err := Change(folders, stored, []any{gone, "relative"}); err == nil {
+		t.Error("an added relative folder should be rejected")
+	}
+	if err := Change(folders, nil, []any{gone}); err == nil {
+		t.Error("without a stored value every item is checked")
+	}
+	theme := copilot.SchemaField{Name: "theme", Type: "enum", Options: []string{"auto", "dark"}}
+	if err := Change(theme, "neon", "neon"); err == nil {
+		t.Error("values other than lists are checked like Value")
+	}
+}
+
+// UT-VAL-008: Settings reports every invalid key with a schema field and ignores the rest
+func TestSettings(t *testing.T) {
+	schema := []copilot.SchemaField{
+		{Name: "theme", Type: "enum", Options: []string{"dark", "light"}},
+		{Name: "beep", Type: "bool"},
+	}
+	if err := Settings(schema, map[string]any{"theme": "dark", "unknown": 3}); err != nil {
+		t.Errorf("valid settings rejected: %v", err)
+	}
+	err := Settings(schema, map[string]any{"theme": "blue", "beep": "yes"})
+	if !errors.Is(err, copilot.ErrInvalidValue) || !strings.Contains(err.Error(), "theme") || !strings.Contains(err.Error(), "beep") {
+		t.Errorf("Settings() = %v, want both invalid keys reported", err)
+	}
+}